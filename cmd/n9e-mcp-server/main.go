@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/n9e/n9e-mcp-server/internal"
+	"github.com/n9e/n9e-mcp-server/pkg/api"
+	"github.com/n9e/n9e-mcp-server/pkg/client"
 	"github.com/n9e/n9e-mcp-server/pkg/toolset"
 
 	"github.com/spf13/cobra"
@@ -42,6 +44,13 @@ var stdioCmd = &cobra.Command{
 	RunE:  runStdio,
 }
 
+var httpCmd = &cobra.Command{
+	Use:   "http",
+	Short: "Run in streamable HTTP mode",
+	Long:  "Run the MCP server over the MCP streamable HTTP transport, so multiple clients can share one server",
+	RunE:  runHTTP,
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
@@ -62,6 +71,10 @@ func init() {
 	rootCmd.PersistentFlags().StringSlice("toolsets", toolset.DefaultToolsets, "Enabled toolsets (env: N9E_TOOLSETS)")
 	rootCmd.PersistentFlags().Bool("read-only", false, "Read-only mode, disable write operations (env: N9E_READ_ONLY)")
 	rootCmd.PersistentFlags().String("log-file", "", "Log file path (default: stderr)")
+	rootCmd.PersistentFlags().String("config", "", "Config file (yaml/json/toml) defining multiple Nightingale instances (env: N9E_CONFIG)")
+	rootCmd.PersistentFlags().String("default-instance", "", "Default instance name when multiple instances are configured (env: N9E_DEFAULT_INSTANCE)")
+	rootCmd.PersistentFlags().Duration("alerts-poll-interval", api.DefaultAlertsPollInterval, "Poll interval of subscribed active alerts resources (env: N9E_ALERTS_POLL_INTERVAL)")
+	rootCmd.PersistentFlags().Duration("alerts-poll-max-backoff", api.DefaultAlertsPollMaxBackoff, "Longest poll delay of active alerts resources after failures (env: N9E_ALERTS_POLL_MAX_BACKOFF)")
 
 	// Bind to viper
 	viper.BindPFlag("token", rootCmd.PersistentFlags().Lookup("token"))
@@ -69,24 +82,113 @@ func init() {
 	viper.BindPFlag("toolsets", rootCmd.PersistentFlags().Lookup("toolsets"))
 	viper.BindPFlag("read_only", rootCmd.PersistentFlags().Lookup("read-only"))
 	viper.BindPFlag("log_file", rootCmd.PersistentFlags().Lookup("log-file"))
+	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
+	viper.BindPFlag("default_instance", rootCmd.PersistentFlags().Lookup("default-instance"))
+	viper.BindPFlag("alerts_poll_interval", rootCmd.PersistentFlags().Lookup("alerts-poll-interval"))
+	viper.BindPFlag("alerts_poll_max_backoff", rootCmd.PersistentFlags().Lookup("alerts-poll-max-backoff"))
+
+	// HTTP mode flags
+	httpCmd.Flags().String("listen", internal.DefaultHTTPListenAddr, "HTTP listen address (env: N9E_HTTP_LISTEN)")
+	httpCmd.Flags().String("path", internal.DefaultHTTPPath, "HTTP path serving MCP requests (env: N9E_HTTP_PATH)")
+	httpCmd.Flags().Duration("session-timeout", internal.DefaultHTTPSessionTimeout, "Close sessions idle for this duration, 0 to disable (env: N9E_HTTP_SESSION_TIMEOUT)")
+	httpCmd.Flags().Bool("stateless", false, "Stateless mode, do not track sessions (env: N9E_HTTP_STATELESS)")
+
+	viper.BindPFlag("http_listen", httpCmd.Flags().Lookup("listen"))
+	viper.BindPFlag("http_path", httpCmd.Flags().Lookup("path"))
+	viper.BindPFlag("http_session_timeout", httpCmd.Flags().Lookup("session-timeout"))
+	viper.BindPFlag("http_stateless", httpCmd.Flags().Lookup("stateless"))
 
 	// Add subcommands
 	rootCmd.AddCommand(stdioCmd)
+	rootCmd.AddCommand(httpCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
+// loadInstances reads named Nightingale instances from the config file, if any.
+//
+// Example config file:
+//
+//	default_instance: prod
+//	instances:
+//	  prod:
+//	    base_url: http://n9e-prod:17000
+//	    token: xxx
+//	  staging:
+//	    base_url: http://n9e-staging:17000
+//	    token: yyy
+func loadInstances() (map[string]client.InstanceConfig, error) {
+	configFile := viper.GetString("config")
+	if configFile == "" {
+		return nil, nil
+	}
+
+	viper.SetConfigFile(configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var instances map[string]client.InstanceConfig
+	if err := viper.UnmarshalKey("instances", &instances); err != nil {
+		return nil, fmt.Errorf("failed to parse instances in config file: %w", err)
+	}
+	return instances, nil
+}
+
 func runStdio(cmd *cobra.Command, args []string) error {
+	instances, err := loadInstances()
+	if err != nil {
+		return err
+	}
+
 	token := viper.GetString("token")
-	if token == "" {
+	if len(instances) == 0 && token == "" {
 		return fmt.Errorf("N9E_TOKEN is required. Set it via --token flag or N9E_TOKEN environment variable")
 	}
+	// There is no session token in stdio mode, every instance needs its own
+	for name, inst := range instances {
+		if inst.Token == "" {
+			return fmt.Errorf("token is required for instance %s in stdio mode", name)
+		}
+	}
 
 	return internal.RunStdioServer(internal.StdioServerConfig{
 		Version:         version,
 		Token:           token,
 		BaseURL:         viper.GetString("base_url"),
+		Instances:       instances,
+		DefaultInstance: viper.GetString("default_instance"),
 		EnabledToolsets: viper.GetStringSlice("toolsets"),
 		ReadOnly:        viper.GetBool("read_only"),
 		LogFilePath:     viper.GetString("log_file"),
+
+		AlertsPollInterval:   viper.GetDuration("alerts_poll_interval"),
+		AlertsPollMaxBackoff: viper.GetDuration("alerts_poll_max_backoff"),
+	})
+}
+
+func runHTTP(cmd *cobra.Command, args []string) error {
+	instances, err := loadInstances()
+	if err != nil {
+		return err
+	}
+
+	// Token is optional in HTTP mode: each session may bring its own token,
+	// N9E_TOKEN is only used as a fallback for sessions without one
+	return internal.RunHTTPServer(internal.HTTPServerConfig{
+		Version:         version,
+		Token:           viper.GetString("token"),
+		BaseURL:         viper.GetString("base_url"),
+		Instances:       instances,
+		DefaultInstance: viper.GetString("default_instance"),
+		EnabledToolsets: viper.GetStringSlice("toolsets"),
+		ReadOnly:        viper.GetBool("read_only"),
+		LogFilePath:     viper.GetString("log_file"),
+		ListenAddr:      viper.GetString("http_listen"),
+		Path:            viper.GetString("http_path"),
+		SessionTimeout:  viper.GetDuration("http_session_timeout"),
+		Stateless:       viper.GetBool("http_stateless"),
+
+		AlertsPollInterval:   viper.GetDuration("alerts_poll_interval"),
+		AlertsPollMaxBackoff: viper.GetDuration("alerts_poll_max_backoff"),
 	})
 }
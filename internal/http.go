@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/n9e/n9e-mcp-server/pkg/client"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	DefaultHTTPListenAddr     = ":8080"
+	DefaultHTTPPath           = "/mcp"
+	DefaultHTTPSessionTimeout = 30 * time.Minute
+	httpShutdownTimeout       = 10 * time.Second
+)
+
+// HTTPServerConfig represents streamable HTTP mode configuration
+type HTTPServerConfig struct {
+	Version         string
+	Token           string // Optional default token for sessions without their own
+	BaseURL         string
+	Instances       map[string]client.InstanceConfig
+	DefaultInstance string
+	EnabledToolsets []string
+	ReadOnly        bool
+	LogFilePath     string
+	ListenAddr      string        // Listen address, e.g. ":8080"
+	Path            string        // HTTP path serving MCP requests, e.g. "/mcp"
+	SessionTimeout  time.Duration // Idle sessions are closed after this duration (0 = never)
+	Stateless       bool          // Do not track sessions (for load-balanced deployments)
+
+	AlertsPollInterval   time.Duration
+	AlertsPollMaxBackoff time.Duration
+}
+
+// RunHTTPServer runs streamable HTTP mode server
+func RunHTTPServer(cfg HTTPServerConfig) error {
+	// Create context with signal interrupt support
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := setupLogger(cfg.LogFilePath)
+
+	if cfg.ListenAddr == "" {
+		cfg.ListenAddr = DefaultHTTPListenAddr
+	}
+	if cfg.Path == "" {
+		cfg.Path = DefaultHTTPPath
+	}
+
+	logger.Info("starting n9e-mcp-server",
+		"version", cfg.Version,
+		"base_url", cfg.BaseURL,
+		"instances", instanceNames(cfg.Instances),
+		"read_only", cfg.ReadOnly,
+		"toolsets", cfg.EnabledToolsets,
+		"listen", cfg.ListenAddr,
+		"path", cfg.Path,
+		"session_timeout", cfg.SessionTimeout,
+		"stateless", cfg.Stateless,
+		"default_token", cfg.Token != "",
+	)
+
+	// Create MCP Server, shared by all sessions
+	server, err := NewMCPServer(ServerConfig{
+		Version:         cfg.Version,
+		Token:           cfg.Token,
+		BaseURL:         cfg.BaseURL,
+		Instances:       cfg.Instances,
+		DefaultInstance: cfg.DefaultInstance,
+		EnabledToolsets: cfg.EnabledToolsets,
+		ReadOnly:        cfg.ReadOnly,
+
+		AlertsPollInterval:   cfg.AlertsPollInterval,
+		AlertsPollMaxBackoff: cfg.AlertsPollMaxBackoff,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create MCP server: %w", err)
+	}
+
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return server
+	}, &mcp.StreamableHTTPOptions{
+		Stateless:      cfg.Stateless,
+		SessionTimeout: cfg.SessionTimeout,
+		Logger:         logger,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path, handler)
+
+	httpServer := &http.Server{
+		Addr:              cfg.ListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Run server
+	errC := make(chan error, 1)
+	go func() {
+		errC <- httpServer.ListenAndServe()
+	}()
+
+	fmt.Fprintf(os.Stderr, "Nightingale MCP Server running on http://%s%s\n", cfg.ListenAddr, cfg.Path)
+
+	// Wait for exit
+	select {
+	case <-ctx.Done():
+		logger.Info("shutting down server...")
+	case err := <-errC:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("server error", "error", err)
+			return fmt.Errorf("server error: %w", err)
+		}
+		return nil
+	}
+
+	// Graceful shutdown: stop accepting new connections and wait for in-flight requests
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		// Long-lived SSE streams may keep connections open, force close them
+		logger.Warn("graceful shutdown timed out, closing remaining connections", "error", err)
+		httpServer.Close()
+	}
+
+	return nil
+}
@@ -2,11 +2,15 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/n9e/n9e-mcp-server/pkg/api"
 	"github.com/n9e/n9e-mcp-server/pkg/client"
@@ -16,64 +20,182 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+const (
+	// tokenMetaKey is the initialize request _meta key carrying a per-session N9E user token
+	tokenMetaKey = "n9e_token"
+	// tokensMetaKey is the initialize request _meta key carrying per-instance N9E user tokens
+	tokensMetaKey = "n9e_tokens"
+)
+
 // ServerConfig represents MCP Server configuration
 type ServerConfig struct {
 	Version         string
-	Token           string
+	Token           string // Default token, used when a session does not bring its own
 	BaseURL         string
+	Instances       map[string]client.InstanceConfig // Named instances, overrides Token/BaseURL when set
+	DefaultInstance string
 	EnabledToolsets []string
 	ReadOnly        bool
+
+	AlertsPollInterval   time.Duration // Poll interval of subscribed active alerts resources (0 = default)
+	AlertsPollMaxBackoff time.Duration // Longest poll delay after consecutive failures (0 = default)
 }
 
 // NewMCPServer creates MCP Server
 func NewMCPServer(cfg ServerConfig) (*mcp.Server, error) {
-	// Create N9e Client
-	n9eClient, err := client.NewClient(cfg.Token, cfg.BaseURL, fmt.Sprintf("n9e-mcp-server/%s", cfg.Version))
+	// Create N9e instance registry, clients are resolved per instance and session token
+	instances := cfg.Instances
+	if len(instances) == 0 {
+		instances = map[string]client.InstanceConfig{
+			client.DefaultInstanceName: {BaseURL: cfg.BaseURL, Token: cfg.Token},
+		}
+	}
+	registry, err := client.NewRegistry(instances, cfg.DefaultInstance, fmt.Sprintf("n9e-mcp-server/%s", cfg.Version))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create n9e client: %w", err)
 	}
 
+	// Create toolset group
+	toolsetGroup := api.DefaultToolsetGroup(registry.GetClient, cfg.ReadOnly)
+	toolsetGroup.SetInstances(registry.Names())
+	api.RegisterInstancesToolset(toolsetGroup, registry)
+
+	// Determine enabled toolsets
+	enabledToolsets := cfg.EnabledToolsets
+	if len(enabledToolsets) == 0 {
+		enabledToolsets = toolset.DefaultToolsets
+	}
+
+	// Enable toolsets, instance discovery is always available
+	enabledToolsets = append(append([]string{}, enabledToolsets...), "instances")
+	if err := toolsetGroup.EnableToolsets(enabledToolsets); err != nil {
+		return nil, fmt.Errorf("failed to enable toolsets: %w", err)
+	}
+
 	// Create MCP Server
-	server := mcp.NewServer(&mcp.Implementation{
-		Name:    "n9e-mcp-server",
-		Version: cfg.Version,
-	}, &mcp.ServerOptions{
+	opts := &mcp.ServerOptions{
 		Instructions: "Nightingale (n9e) monitoring MCP Server. Provides alert rule management, " +
 			"active/history alert querying, alert mute/silence management, notification rules, " +
 			"alert subscriptions, user/team management, monitored target management, " +
 			"datasource management, business group management, and event pipeline/workflow management.",
 		Logger: slog.Default(),
-	})
+	}
+
+	// Prompt and resource template arguments such as bgid, datasource_id and rule_id are completed from n9e
+	opts.CompletionHandler = api.NewCompleter(registry.GetClient).Complete
+
+	// Active alerts resources can be subscribed to when the alerts toolset is enabled
+	var watcher *api.ActiveAlertsWatcher
+	if toolsetGroup.IsEnabled("alerts") {
+		watcher = api.NewActiveAlertsWatcher(registry.GetClient, cfg.AlertsPollInterval, cfg.AlertsPollMaxBackoff)
+		opts.SubscribeHandler = watcher.Subscribe
+		opts.UnsubscribeHandler = watcher.Unsubscribe
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{
+		Name:    "n9e-mcp-server",
+		Version: cfg.Version,
+	}, opts)
+	if watcher != nil {
+		watcher.SetServer(server)
+	}
+
+	// Register all tools, resource templates and prompts
+	toolsetGroup.RegisterAll(server)
+
+	// resources/list enumerates the business groups accessible to the session user
+	if toolsetGroup.IsEnabled("busi_groups") {
+		server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+			return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+				result, err := next(ctx, method, req)
+				listResult, ok := result.(*mcp.ListResourcesResult)
+				if err != nil || !ok || listResult.NextCursor != "" {
+					return result, err
+				}
 
-	// Add middleware: inject client into context
+				c := registry.GetClient(ctx)
+				if c == nil {
+					return listResult, nil
+				}
+				groups, err := api.ListBusiGroupResources(ctx, c)
+				if err != nil {
+					// Best effort, templates and static resources are still listed
+					slog.Warn("failed to list business group resources", "error", err)
+					return listResult, nil
+				}
+				listResult.Resources = append(listResult.Resources, groups...)
+				return listResult, nil
+			}
+		})
+	}
+
+	// Add middleware: inject the session's tokens and the target instance into context.
+	// Added last so that it runs first, before the middleware above.
 	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
 		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
-			ctx = client.ContextWithClient(ctx, n9eClient)
+			ctx = client.ContextWithSessionTokens(ctx, requestTokens(req))
+			if callReq, ok := req.(*mcp.CallToolRequest); ok {
+				instance := requestInstance(callReq)
+				if instance != "" && !registry.Has(instance) {
+					return toolset.NewToolResultError(fmt.Sprintf("unknown instance: %s, available instances: %v", instance, registry.Names())), nil
+				}
+				ctx = client.ContextWithInstance(ctx, instance)
+			}
 			return next(ctx, method, req)
 		}
 	})
 
-	// Create toolset group
-	getClient := func(ctx context.Context) *client.Client {
-		return client.ClientFromContext(ctx)
+	return server, nil
+}
+
+// requestTokens extracts the N9E user tokens brought by the request or its session.
+// Default token lookup order: X-User-Token header, Authorization bearer header, initialize _meta.n9e_token.
+// Per-instance tokens are read from initialize _meta.n9e_tokens.
+func requestTokens(req mcp.Request) client.SessionTokens {
+	var tokens client.SessionTokens
+
+	var meta mcp.Meta
+	if ss, ok := req.GetSession().(*mcp.ServerSession); ok {
+		if params := ss.InitializeParams(); params != nil {
+			meta = params.Meta
+		}
 	}
-	toolsetGroup := api.DefaultToolsetGroup(getClient, cfg.ReadOnly)
 
-	// Determine enabled toolsets
-	enabledToolsets := cfg.EnabledToolsets
-	if len(enabledToolsets) == 0 {
-		enabledToolsets = toolset.DefaultToolsets
+	if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+		if token := extra.Header.Get("X-User-Token"); token != "" {
+			tokens.Default = token
+		} else if auth := extra.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+			tokens.Default = strings.TrimSpace(auth[7:])
+		}
+	}
+	if tokens.Default == "" {
+		tokens.Default, _ = meta[tokenMetaKey].(string)
 	}
 
-	// Enable toolsets
-	if err := toolsetGroup.EnableToolsets(enabledToolsets); err != nil {
-		return nil, fmt.Errorf("failed to enable toolsets: %w", err)
+	if perInstance, ok := meta[tokensMetaKey].(map[string]any); ok {
+		tokens.PerInstance = make(map[string]string, len(perInstance))
+		for name, v := range perInstance {
+			if token, ok := v.(string); ok {
+				tokens.PerInstance[name] = token
+			}
+		}
 	}
 
-	// Register all tools
-	toolsetGroup.RegisterAll(server)
+	return tokens
+}
 
-	return server, nil
+// requestInstance extracts the optional instance argument of a tool call
+func requestInstance(req *mcp.CallToolRequest) string {
+	if req.Params == nil || len(req.Params.Arguments) == 0 {
+		return ""
+	}
+	var args struct {
+		Instance string `json:"instance"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return ""
+	}
+	return args.Instance
 }
 
 // StdioServerConfig represents stdio mode configuration
@@ -81,9 +203,14 @@ type StdioServerConfig struct {
 	Version         string
 	Token           string
 	BaseURL         string
+	Instances       map[string]client.InstanceConfig
+	DefaultInstance string
 	EnabledToolsets []string
 	ReadOnly        bool
 	LogFilePath     string
+
+	AlertsPollInterval   time.Duration
+	AlertsPollMaxBackoff time.Duration
 }
 
 // RunStdioServer runs stdio mode server
@@ -92,46 +219,12 @@ func RunStdioServer(cfg StdioServerConfig) error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
-	// Configure logging (with file rotation support)
-	var logOutput io.Writer = os.Stderr
-	if cfg.LogFilePath != "" {
-		logOutput = &lumberjack.Logger{
-			Filename:   cfg.LogFilePath,
-			MaxSize:    100, // MB, max file size
-			MaxBackups: 3,   // Number of old files to keep
-			MaxAge:     7,   // Days to keep
-			Compress:   true,
-		}
-	}
-
-	// Use LevelVar to support dynamic log level modification at runtime
-	var logLevel slog.LevelVar
-	parseLogLevel := func() slog.Level {
-		switch os.Getenv("N9E_MCP_LOG_LEVEL") {
-		case "debug", "DEBUG":
-			return slog.LevelDebug
-		case "warn", "WARN":
-			return slog.LevelWarn
-		case "error", "ERROR":
-			return slog.LevelError
-		default:
-			return slog.LevelInfo
-		}
-	}
-	logLevel.Set(parseLogLevel())
-	logger := slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: &logLevel}))
-	slog.SetDefault(logger)
-
-	// Listen to SIGUSR1 signal to reload environment variables and update log level (Unix only)
-	setupSignalReload(func() {
-		newLevel := parseLogLevel()
-		logLevel.Set(newLevel)
-		logger.Info("log level reloaded", "level", newLevel.String())
-	})
+	logger := setupLogger(cfg.LogFilePath)
 
 	logger.Info("starting n9e-mcp-server",
 		"version", cfg.Version,
 		"base_url", cfg.BaseURL,
+		"instances", instanceNames(cfg.Instances),
 		"read_only", cfg.ReadOnly,
 		"toolsets", cfg.EnabledToolsets,
 	)
@@ -141,8 +234,13 @@ func RunStdioServer(cfg StdioServerConfig) error {
 		Version:         cfg.Version,
 		Token:           cfg.Token,
 		BaseURL:         cfg.BaseURL,
+		Instances:       cfg.Instances,
+		DefaultInstance: cfg.DefaultInstance,
 		EnabledToolsets: cfg.EnabledToolsets,
 		ReadOnly:        cfg.ReadOnly,
+
+		AlertsPollInterval:   cfg.AlertsPollInterval,
+		AlertsPollMaxBackoff: cfg.AlertsPollMaxBackoff,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create MCP server: %w", err)
@@ -169,3 +267,55 @@ func RunStdioServer(cfg StdioServerConfig) error {
 
 	return nil
 }
+
+// setupLogger configures the default slog logger (with file rotation and SIGUSR1 level reload support)
+func setupLogger(logFilePath string) *slog.Logger {
+	// Configure logging (with file rotation support)
+	var logOutput io.Writer = os.Stderr
+	if logFilePath != "" {
+		logOutput = &lumberjack.Logger{
+			Filename:   logFilePath,
+			MaxSize:    100, // MB, max file size
+			MaxBackups: 3,   // Number of old files to keep
+			MaxAge:     7,   // Days to keep
+			Compress:   true,
+		}
+	}
+
+	// Use LevelVar to support dynamic log level modification at runtime
+	var logLevel slog.LevelVar
+	parseLogLevel := func() slog.Level {
+		switch os.Getenv("N9E_MCP_LOG_LEVEL") {
+		case "debug", "DEBUG":
+			return slog.LevelDebug
+		case "warn", "WARN":
+			return slog.LevelWarn
+		case "error", "ERROR":
+			return slog.LevelError
+		default:
+			return slog.LevelInfo
+		}
+	}
+	logLevel.Set(parseLogLevel())
+	logger := slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: &logLevel}))
+	slog.SetDefault(logger)
+
+	// Listen to SIGUSR1 signal to reload environment variables and update log level (Unix only)
+	setupSignalReload(func() {
+		newLevel := parseLogLevel()
+		logLevel.Set(newLevel)
+		logger.Info("log level reloaded", "level", newLevel.String())
+	})
+
+	return logger
+}
+
+// instanceNames returns sorted instance names for logging
+func instanceNames(instances map[string]client.InstanceConfig) []string {
+	names := make([]string, 0, len(instances))
+	for name := range instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -2,8 +2,11 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/n9e/n9e-mcp-server/pkg/client"
 	"github.com/n9e/n9e-mcp-server/pkg/toolset"
@@ -28,6 +31,47 @@ type GetAlertSubscribeInput struct {
 	SubscribeId int64 `json:"sid"`
 }
 
+// AlertSubscribeFields represents alert subscription fields, omitted fields are left unchanged on update
+type AlertSubscribeFields struct {
+	Name             *string           `json:"name,omitempty"`
+	Note             *string           `json:"note,omitempty"`
+	Disabled         *int              `json:"disabled,omitempty"`
+	Prod             *string           `json:"prod,omitempty"`
+	Cate             *string           `json:"cate,omitempty"`
+	DatasourceIds    []int64           `json:"datasource_ids,omitempty"`
+	RuleIds          []int64           `json:"rule_ids,omitempty"`
+	Severities       []int             `json:"severities,omitempty"`
+	Tags             []types.TagFilter `json:"tags,omitempty"`
+	ForDuration      *int64            `json:"for_duration,omitempty"`
+	RedefineSeverity *int              `json:"redefine_severity,omitempty"`
+	NewSeverity      *int              `json:"new_severity,omitempty"`
+	RedefineChannels *int              `json:"redefine_channels,omitempty"`
+	NewChannels      []string          `json:"new_channels,omitempty"`
+	UserGroupIds     []int64           `json:"user_group_ids,omitempty"`
+	RedefineWebhooks *int              `json:"redefine_webhooks,omitempty"`
+	Webhooks         []string          `json:"webhooks,omitempty"`
+	NotifyRuleIds    []int64           `json:"notify_rule_ids,omitempty"`
+}
+
+// CreateAlertSubscribeInput represents create alert subscription parameters
+type CreateAlertSubscribeInput struct {
+	GroupId int64 `json:"group_id"`
+	AlertSubscribeFields
+}
+
+// UpdateAlertSubscribeInput represents update alert subscription parameters
+type UpdateAlertSubscribeInput struct {
+	GroupId     int64 `json:"group_id"`
+	SubscribeId int64 `json:"sid"`
+	AlertSubscribeFields
+}
+
+// DeleteAlertSubscribesInput represents delete alert subscriptions parameters
+type DeleteAlertSubscribesInput struct {
+	GroupId      int64   `json:"group_id"`
+	SubscribeIds []int64 `json:"ids"`
+}
+
 // RegisterAlertSubscribesToolset registers alert subscriptions toolset
 func RegisterAlertSubscribesToolset(group *toolset.ToolsetGroup, getClient client.GetClientFunc) {
 	ts := toolset.NewToolset("alert_subscribes", "Alert subscription management tools for event handling")
@@ -38,6 +82,12 @@ func RegisterAlertSubscribesToolset(group *toolset.ToolsetGroup, getClient clien
 		getAlertSubscribeTool(getClient),
 	)
 
+	ts.AddWriteTools(
+		createAlertSubscribeTool(getClient),
+		updateAlertSubscribeTool(getClient),
+		deleteAlertSubscribesTool(getClient),
+	)
+
 	group.AddToolset(ts)
 }
 
@@ -60,6 +110,7 @@ func listAlertSubscribesTool(getClient client.GetClientFunc) toolset.ServerTool
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[[]types.AlertSubscribe](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListAlertSubscribesInput) (*mcp.CallToolResult, error) {
 			if input.GroupId <= 0 {
@@ -100,6 +151,7 @@ func listAlertSubscribesByGidsTool(getClient client.GetClientFunc) toolset.Serve
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[[]types.AlertSubscribe](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListAlertSubscribesByGidsInput) (*mcp.CallToolResult, error) {
 			c := getClient(ctx)
@@ -141,6 +193,7 @@ func getAlertSubscribeTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.AlertSubscribe](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetAlertSubscribeInput) (*mcp.CallToolResult, error) {
 			if input.SubscribeId <= 0 {
@@ -162,3 +215,378 @@ func getAlertSubscribeTool(getClient client.GetClientFunc) toolset.ServerTool {
 		}),
 	)
 }
+
+// alertSubscribeProperties adds the alert subscription fields shared by create and update to props
+func alertSubscribeProperties(props map[string]*jsonschema.Schema) map[string]*jsonschema.Schema {
+	intArray := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{Type: "array", Description: description, Items: &jsonschema.Schema{Type: "integer"}}
+	}
+	stringArray := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{Type: "array", Description: description, Items: &jsonschema.Schema{Type: "string"}}
+	}
+
+	for k, v := range map[string]*jsonschema.Schema{
+		"name":           {Type: "string", Description: "Subscription name"},
+		"note":           {Type: "string", Description: "Subscription note"},
+		"disabled":       {Type: "integer", Description: "Disabled status (0=enabled, 1=disabled)"},
+		"prod":           {Type: "string", Description: "Product type (host/metric/loki/anomaly)", Enum: toolset.EnumValues(toolset.ValidRuleProds)},
+		"cate":           {Type: "string", Description: "Datasource category (prometheus/host/elasticsearch/loki)", Enum: toolset.EnumValues(toolset.ValidRuleCates)},
+		"datasource_ids": intArray("Datasource IDs to match (empty means all)"),
+		"rule_ids":       intArray("Alert rule IDs to subscribe to, may belong to other business groups (empty means all)"),
+		"severities":     intArray("Severity levels to match (1=critical, 2=warning, 3=info)"),
+		"tags": {
+			Type:        "array",
+			Description: "Event tag filters. Each filter has key, func (==, !=, in, not in, =~, !~), and value",
+			Items: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"key":   {Type: "string", Description: "Tag key"},
+					"func":  {Type: "string", Description: "Operator: ==, !=, in, not in, =~, !~"},
+					"value": {Type: "string", Description: "Tag value (for 'in'/'not in', space-separated values)"},
+				},
+			},
+		},
+		"for_duration":      {Type: "integer", Description: "Only match events lasting at least this many seconds"},
+		"redefine_severity": {Type: "integer", Description: "Redefine the severity of matched events (0=no, 1=yes)"},
+		"new_severity":      {Type: "integer", Description: "New severity when redefine_severity=1 (1=critical, 2=warning, 3=info)"},
+		"redefine_channels": {Type: "integer", Description: "Redefine notify channels of matched events (0=no, 1=yes)"},
+		"new_channels":      stringArray("Notify channel idents when redefine_channels=1, e.g. email, dingtalk"),
+		"user_group_ids":    intArray("User group IDs to notify"),
+		"redefine_webhooks": {Type: "integer", Description: "Redefine webhooks of matched events (0=no, 1=yes)"},
+		"webhooks":          stringArray("Webhook URLs when redefine_webhooks=1"),
+		"notify_rule_ids":   intArray("Notification rule IDs to notify with (Nightingale v8+)"),
+	} {
+		props[k] = v
+	}
+	return props
+}
+
+// apply sets the given fields on a subscription in the API format
+func (f AlertSubscribeFields) apply(sub map[string]any) {
+	if f.Name != nil {
+		sub["name"] = strings.TrimSpace(*f.Name)
+	}
+	if f.Note != nil {
+		sub["note"] = *f.Note
+	}
+	if f.Disabled != nil {
+		sub["disabled"] = *f.Disabled
+	}
+	if f.Prod != nil {
+		sub["prod"] = *f.Prod
+	}
+	if f.Cate != nil {
+		sub["cate"] = *f.Cate
+	}
+	if f.DatasourceIds != nil {
+		sub["datasource_ids"] = f.DatasourceIds
+	}
+	if f.RuleIds != nil {
+		sub["rule_ids"] = f.RuleIds
+	}
+	if f.Severities != nil {
+		sub["severities"] = f.Severities
+	}
+	if f.Tags != nil {
+		sub["tags"] = f.Tags
+	}
+	if f.ForDuration != nil {
+		sub["for_duration"] = *f.ForDuration
+	}
+	if f.RedefineSeverity != nil {
+		sub["redefine_severity"] = *f.RedefineSeverity
+	}
+	if f.NewSeverity != nil {
+		sub["new_severity"] = *f.NewSeverity
+	}
+	if f.RedefineChannels != nil {
+		sub["redefine_channels"] = *f.RedefineChannels
+	}
+	// Channels and user groups are stored as space-separated strings
+	if f.NewChannels != nil {
+		sub["new_channels"] = strings.Join(f.NewChannels, " ")
+	}
+	if f.UserGroupIds != nil {
+		ids := make([]string, 0, len(f.UserGroupIds))
+		for _, id := range f.UserGroupIds {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		sub["user_group_ids"] = strings.Join(ids, " ")
+	}
+	if f.RedefineWebhooks != nil {
+		sub["redefine_webhooks"] = *f.RedefineWebhooks
+	}
+	if f.Webhooks != nil {
+		sub["webhooks"] = f.Webhooks
+	}
+	if f.NotifyRuleIds != nil {
+		sub["notify_rule_ids"] = f.NotifyRuleIds
+	}
+}
+
+// validateAlertSubscribe validates a subscription in the API format.
+// When changed is not nil only the fields it contains are validated,
+// existing subscriptions may use values outside the enums.
+func validateAlertSubscribe(sub, changed map[string]any) error {
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return err
+	}
+	var s struct {
+		Name             string            `json:"name"`
+		Disabled         int               `json:"disabled"`
+		Prod             string            `json:"prod"`
+		Cate             string            `json:"cate"`
+		Severities       []int             `json:"severities"`
+		Tags             []types.TagFilter `json:"tags"`
+		ForDuration      int64             `json:"for_duration"`
+		RedefineSeverity int               `json:"redefine_severity"`
+		NewSeverity      int               `json:"new_severity"`
+		RedefineChannels int               `json:"redefine_channels"`
+		NewChannels      string            `json:"new_channels"`
+		RedefineWebhooks int               `json:"redefine_webhooks"`
+		Webhooks         []string          `json:"webhooks"`
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	has := func(keys ...string) bool {
+		if changed == nil {
+			return true
+		}
+		for _, k := range keys {
+			if _, ok := changed[k]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if has("name") && s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if has("disabled") && s.Disabled != 0 && s.Disabled != 1 {
+		return fmt.Errorf("invalid disabled: %d, must be 0 or 1", s.Disabled)
+	}
+	if has("prod") {
+		if err := toolset.ValidateRuleProds(s.Prod); err != nil {
+			return err
+		}
+	}
+	if has("cate") {
+		if err := toolset.ValidateRuleCate(s.Cate); err != nil {
+			return err
+		}
+	}
+	if has("severities") {
+		severities := make([]string, 0, len(s.Severities))
+		for _, sev := range s.Severities {
+			severities = append(severities, strconv.Itoa(sev))
+		}
+		if err := toolset.ValidateSeverity(strings.Join(severities, ",")); err != nil {
+			return err
+		}
+	}
+	if has("tags") {
+		if _, err := newTagMatcher(s.Tags); err != nil {
+			return err
+		}
+	}
+	if has("for_duration") && s.ForDuration < 0 {
+		return fmt.Errorf("for_duration must be >= 0")
+	}
+	if has("redefine_severity", "new_severity") && s.RedefineSeverity == 1 {
+		if err := toolset.ValidateSeverity(strconv.Itoa(s.NewSeverity)); err != nil {
+			return fmt.Errorf("new_severity is required when redefine_severity=1: %w", err)
+		}
+	}
+	if has("redefine_channels", "new_channels") && s.RedefineChannels == 1 && strings.TrimSpace(s.NewChannels) == "" {
+		return fmt.Errorf("new_channels is required when redefine_channels=1")
+	}
+	if has("redefine_webhooks", "webhooks") && s.RedefineWebhooks == 1 && len(s.Webhooks) == 0 {
+		return fmt.Errorf("webhooks is required when redefine_webhooks=1")
+	}
+	return nil
+}
+
+func createAlertSubscribeTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "create_alert_subscribe",
+			Description: "Create an alert subscription in a business group. Subscriptions can match rules of other business groups and redefine severity, channels and webhooks of matched events.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Create Alert Subscription",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"group_id", "name"},
+				Properties: alertSubscribeProperties(map[string]*jsonschema.Schema{
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID the subscription belongs to",
+					},
+				}),
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input CreateAlertSubscribeInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("group_id is required and must be positive"), nil
+			}
+
+			sub := map[string]any{
+				"group_id":       input.GroupId,
+				"datasource_ids": []int64{},
+				"rule_ids":       []int64{},
+				"severities":     []int{},
+				"tags":           []types.TagFilter{},
+				"webhooks":       []string{},
+				"busi_groups":    []types.TagFilter{},
+			}
+			input.apply(sub)
+			if err := validateAlertSubscribe(sub, nil); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/alert-subscribes", input.GroupId)
+			result, err := client.DoPost[int64](c, ctx, path, sub)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      result,
+				Message: "Alert subscription created successfully",
+			}), nil
+		}),
+	)
+}
+
+func updateAlertSubscribeTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "update_alert_subscribe",
+			Description: "Update an alert subscription. Omitted fields are left unchanged, given lists replace the current ones.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Update Alert Subscription",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"group_id", "sid"},
+				Properties: alertSubscribeProperties(map[string]*jsonschema.Schema{
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID the subscription belongs to",
+					},
+					"sid": {
+						Type:        "integer",
+						Description: "Alert subscription ID",
+					},
+				}),
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UpdateAlertSubscribeInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("group_id is required and must be positive"), nil
+			}
+			if input.SubscribeId <= 0 {
+				return toolset.NewToolResultError("sid is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			// Fetch the current subscription and overlay the given fields
+			sub, err := client.DoGet[map[string]any](c, ctx, fmt.Sprintf("/api/n9e/alert-subscribe/%d", input.SubscribeId), nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+			if gid, _ := sub["group_id"].(float64); int64(gid) != input.GroupId {
+				return toolset.NewToolResultError(fmt.Sprintf("alert subscription %d does not belong to business group %d", input.SubscribeId, input.GroupId)), nil
+			}
+			changed := make(map[string]any)
+			input.apply(changed)
+			input.apply(sub)
+			if err := validateAlertSubscribe(sub, changed); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/alert-subscribes", input.GroupId)
+			if _, err := client.DoPut[any](c, ctx, path, sub); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      input.SubscribeId,
+				Message: "Alert subscription updated successfully",
+			}), nil
+		}),
+	)
+}
+
+func deleteAlertSubscribesTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "delete_alert_subscribes",
+			Description: "Delete alert subscriptions by ID",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Delete Alert Subscriptions",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(true),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"group_id", "ids"},
+				Properties: map[string]*jsonschema.Schema{
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID",
+					},
+					"ids": {
+						Type:        "array",
+						Description: "Alert subscription IDs to delete",
+						Items:       &jsonschema.Schema{Type: "integer"},
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input DeleteAlertSubscribesInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("group_id is required and must be positive"), nil
+			}
+			if len(input.SubscribeIds) == 0 {
+				return toolset.NewToolResultError("ids is required"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/alert-subscribes", input.GroupId)
+			if _, err := client.DoDelete[any](c, ctx, path, map[string]any{"ids": input.SubscribeIds}); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Ids:     input.SubscribeIds,
+				Message: fmt.Sprintf("%d alert subscription(s) deleted successfully", len(input.SubscribeIds)),
+			}), nil
+		}),
+	)
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 
@@ -14,6 +15,9 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// activeAlertsPageSize is the page size used when listing all active alerts
+const activeAlertsPageSize = 100
+
 // ListActiveAlertsInput represents active alerts query parameters
 type ListActiveAlertsInput struct {
 	Hours         int64  `json:"hours,omitempty"`
@@ -63,6 +67,71 @@ type GetAlertRuleInput struct {
 	RuleId int64 `json:"arid"`
 }
 
+// CreateAlertRuleInput represents create alert rule parameters
+type CreateAlertRuleInput struct {
+	GroupId int64           `json:"group_id"`
+	Rule    types.AlertRule `json:"rule"`
+}
+
+// UpdateAlertRuleInput represents patch alert rule parameters
+type UpdateAlertRuleInput struct {
+	GroupId int64          `json:"group_id"`
+	RuleId  int64          `json:"arid"`
+	Fields  map[string]any `json:"fields"`
+}
+
+// CloneAlertRuleInput represents clone alert rule parameters
+type CloneAlertRuleInput struct {
+	RuleId        int64  `json:"arid"`
+	TargetGroupId int64  `json:"target_group_id"`
+	Name          string `json:"name,omitempty"`
+	Disabled      *bool  `json:"disabled,omitempty"`
+}
+
+// SetAlertRulesDisabledInput represents enable/disable alert rules parameters
+type SetAlertRulesDisabledInput struct {
+	GroupId  int64   `json:"group_id"`
+	RuleIds  []int64 `json:"ids"`
+	Disabled bool    `json:"disabled"`
+}
+
+// DeleteAlertRulesInput represents delete alert rules parameters
+type DeleteAlertRulesInput struct {
+	GroupId int64   `json:"group_id"`
+	RuleIds []int64 `json:"ids"`
+}
+
+// ClaimActiveAlertInput represents claim/unclaim active alert parameters
+type ClaimActiveAlertInput struct {
+	EventId int64 `json:"eid"`
+	Unclaim bool  `json:"unclaim,omitempty"`
+}
+
+// DeleteActiveAlertsInput represents delete active alerts parameters
+type DeleteActiveAlertsInput struct {
+	EventIds []int64 `json:"ids"`
+}
+
+// CloneAlertRuleResult represents the result of cloning an alert rule
+type CloneAlertRuleResult struct {
+	SourceId      int64  `json:"source_id"`
+	TargetGroupId int64  `json:"target_group_id"`
+	Name          string `json:"name"`
+	Message       string `json:"message"`
+}
+
+// ClaimActiveAlertResult represents the claimant of an active alert event after claim/unclaim
+type ClaimActiveAlertResult struct {
+	EventId  int64  `json:"eid"`
+	Claimant string `json:"claimant"`
+	Message  string `json:"message"`
+}
+
+// alertRuleImmutableFields are fields that cannot be patched by update_alert_rule, they are cleared when cloning
+var alertRuleImmutableFields = map[string]bool{
+	"id": true, "group_id": true, "create_at": true, "create_by": true, "update_at": true, "update_by": true,
+}
+
 // RegisterAlertsToolset registers alerts toolset
 func RegisterAlertsToolset(group *toolset.ToolsetGroup, getClient client.GetClientFunc) {
 	ts := toolset.NewToolset("alerts", "Alert management tools for viewing and managing alerts")
@@ -77,6 +146,28 @@ func RegisterAlertsToolset(group *toolset.ToolsetGroup, getClient client.GetClie
 		getAlertRuleTool(getClient),
 	)
 
+	// Write tools
+	ts.AddWriteTools(
+		createAlertRuleTool(getClient),
+		updateAlertRuleTool(getClient),
+		cloneAlertRuleTool(getClient),
+		setAlertRulesDisabledTool(getClient),
+		deleteAlertRulesTool(getClient),
+		claimActiveAlertTool(getClient),
+		deleteActiveAlertsTool(getClient),
+	)
+
+	ts.AddResourceTemplates(
+		alertRuleResource(getClient),
+		alertEventResource(getClient),
+		activeAlertsResource(getClient),
+	)
+
+	ts.AddPrompts(
+		triageAlertPrompt(group, getClient),
+		weeklyAlertReviewPrompt(getClient),
+	)
+
 	group.AddToolset(ts)
 }
 
@@ -142,6 +233,7 @@ func listActiveAlertsTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.PageResp[types.AlertCurEvent]](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListActiveAlertsInput) (*mcp.CallToolResult, error) {
 			// Parameter validation
@@ -228,6 +320,7 @@ func getActiveAlertTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.AlertCurEvent](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetAlertInput) (*mcp.CallToolResult, error) {
 			if input.EventId <= 0 {
@@ -312,6 +405,7 @@ func listHistoryAlertsTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.PageResp[types.AlertHisEvent]](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListHistoryAlertsInput) (*mcp.CallToolResult, error) {
 			if err := toolset.ValidateTimeRange(input.Hours, input.Stime, input.Etime); err != nil {
@@ -393,6 +487,7 @@ func getHistoryAlertTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.AlertHisEvent](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetAlertInput) (*mcp.CallToolResult, error) {
 			if input.EventId <= 0 {
@@ -434,6 +529,7 @@ func listAlertRulesTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[[]types.AlertRule](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListAlertRulesInput) (*mcp.CallToolResult, error) {
 			if input.GroupId <= 0 {
@@ -475,6 +571,7 @@ func getAlertRuleTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.AlertRule](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetAlertRuleInput) (*mcp.CallToolResult, error) {
 			if input.RuleId <= 0 {
@@ -496,3 +593,573 @@ func getAlertRuleTool(getClient client.GetClientFunc) toolset.ServerTool {
 		}),
 	)
 }
+
+// alertRuleSchema describes the commonly used alert rule fields
+func alertRuleSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "object",
+		Description: "Alert rule definition. Use get_alert_rule on an existing rule as a reference for rule_config and other fields.",
+		Properties: map[string]*jsonschema.Schema{
+			"name":               {Type: "string", Description: "Rule name"},
+			"note":               {Type: "string", Description: "Rule note/description"},
+			"prod":               {Type: "string", Description: "Product type (metric/host/loki/anomaly)", Enum: toolset.EnumValues(toolset.ValidRuleProds)},
+			"cate":               {Type: "string", Description: "Datasource category (prometheus/host/elasticsearch/loki)", Enum: toolset.EnumValues(toolset.ValidRuleCates)},
+			"datasource_ids":     {Type: "array", Description: "Datasource IDs", Items: &jsonschema.Schema{Type: "integer"}},
+			"prom_ql":            {Type: "string", Description: "PromQL expression (legacy single query rules)"},
+			"rule_config":        {Type: "object", Description: "Rule configuration (queries, triggers, etc.)"},
+			"severity":           {Type: "integer", Description: "Severity (1=critical, 2=warning, 3=info)"},
+			"disabled":           {Type: "integer", Description: "Disabled status (0=enabled, 1=disabled)"},
+			"prom_for_duration":  {Type: "integer", Description: "Duration in seconds the condition must hold before firing"},
+			"prom_eval_interval": {Type: "integer", Description: "Evaluation interval in seconds"},
+			"notify_recovered":   {Type: "integer", Description: "Notify on recovery (0=no, 1=yes)"},
+			"notify_repeat_step": {Type: "integer", Description: "Repeat notification interval in minutes"},
+			"notify_max_number":  {Type: "integer", Description: "Maximum number of notifications (0=unlimited)"},
+			"notify_version":     {Type: "integer", Description: "Notification version (0=legacy channels, 1=notify rules)"},
+			"notify_rule_ids":    {Type: "array", Description: "Notification rule IDs (when notify_version=1)", Items: &jsonschema.Schema{Type: "integer"}},
+			"append_tags":        {Type: "array", Description: "Tags appended to events, in key=value format", Items: &jsonschema.Schema{Type: "string"}},
+			"annotations":        {Type: "object", Description: "Event annotations"},
+			"runbook_url":        {Type: "string", Description: "Runbook URL"},
+			"enable_in_bg":       {Type: "integer", Description: "Only match targets in this business group (0=no, 1=yes)"},
+		},
+	}
+}
+
+// validateAlertRuleFields validates enum-valued alert rule fields
+func validateAlertRuleFields(cate, prod string, severity int) error {
+	if err := toolset.ValidateRuleCate(cate); err != nil {
+		return err
+	}
+	if err := toolset.ValidateRuleProds(prod); err != nil {
+		return err
+	}
+	if severity != 0 && !toolset.ValidSeverities[severity] {
+		return fmt.Errorf("invalid severity value: %d, must be 1, 2, or 3", severity)
+	}
+	return nil
+}
+
+func createAlertRuleTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "create_alert_rule",
+			Description: "Create a new alert rule in a business group",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Create Alert Rule",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"group_id", "rule"},
+				Properties: map[string]*jsonschema.Schema{
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID",
+					},
+					"rule": alertRuleSchema(),
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input CreateAlertRuleInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("group_id is required and must be positive"), nil
+			}
+			if input.Rule.Name == "" {
+				return toolset.NewToolResultError("rule.name is required"), nil
+			}
+			if err := validateAlertRuleFields(input.Rule.Cate, input.Rule.Prod, input.Rule.Severity); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			rule := input.Rule
+			rule.Id = 0
+			rule.GroupId = input.GroupId
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/alert-rules", input.GroupId)
+			result, err := client.DoPost[map[string]string](c, ctx, path, []types.AlertRule{rule})
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+			if msg := result[rule.Name]; msg != "" {
+				return toolset.NewToolResultError(fmt.Sprintf("failed to create alert rule %s: %s", rule.Name, msg)), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Name:    rule.Name,
+				Message: "Alert rule created successfully",
+			}), nil
+		}),
+	)
+}
+
+func updateAlertRuleTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "update_alert_rule",
+			Description: "Update selected fields of an existing alert rule. Only the given fields are changed, other fields keep their current values.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Update Alert Rule",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"group_id", "arid", "fields"},
+				Properties: map[string]*jsonschema.Schema{
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID the rule belongs to",
+					},
+					"arid": {
+						Type:        "integer",
+						Description: "Alert rule ID",
+					},
+					"fields": func() *jsonschema.Schema {
+						s := alertRuleSchema()
+						s.Description = "Fields to change, same names as in get_alert_rule output (id, group_id and audit fields cannot be changed)"
+						return s
+					}(),
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UpdateAlertRuleInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("group_id is required and must be positive"), nil
+			}
+			if input.RuleId <= 0 {
+				return toolset.NewToolResultError("arid is required and must be positive"), nil
+			}
+			if len(input.Fields) == 0 {
+				return toolset.NewToolResultError("fields is required"), nil
+			}
+			for k := range input.Fields {
+				if alertRuleImmutableFields[k] {
+					return toolset.NewToolResultError(fmt.Sprintf("field %s cannot be changed", k)), nil
+				}
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			// Fetch the current rule and overlay the given fields
+			rule, err := client.DoGet[map[string]any](c, ctx, fmt.Sprintf("/api/n9e/alert-rule/%d", input.RuleId), nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+			if gid, _ := rule["group_id"].(float64); int64(gid) != input.GroupId {
+				return toolset.NewToolResultError(fmt.Sprintf("alert rule %d does not belong to business group %d", input.RuleId, input.GroupId)), nil
+			}
+			for k, v := range input.Fields {
+				rule[k] = v
+			}
+
+			// Only the patched fields are validated, existing rules may use values outside the enums
+			cate, _ := input.Fields["cate"].(string)
+			prod, _ := input.Fields["prod"].(string)
+			severity, _ := input.Fields["severity"].(float64)
+			if err := validateAlertRuleFields(cate, prod, int(severity)); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/alert-rule/%d", input.GroupId, input.RuleId)
+			if _, err := client.DoPut[any](c, ctx, path, rule); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      input.RuleId,
+				Message: "Alert rule updated successfully",
+			}), nil
+		}),
+	)
+}
+
+func cloneAlertRuleTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "clone_alert_rule",
+			Description: "Clone an existing alert rule into a business group (the same or another one)",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Clone Alert Rule",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"arid", "target_group_id"},
+				Properties: map[string]*jsonschema.Schema{
+					"arid": {
+						Type:        "integer",
+						Description: "Source alert rule ID",
+					},
+					"target_group_id": {
+						Type:        "integer",
+						Description: "Business group ID to clone the rule into",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the cloned rule (default: same as source, must be unique in the target group)",
+					},
+					"disabled": {
+						Type:        "boolean",
+						Description: "Create the cloned rule disabled (default: same as source)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[CloneAlertRuleResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input CloneAlertRuleInput) (*mcp.CallToolResult, error) {
+			if input.RuleId <= 0 {
+				return toolset.NewToolResultError("arid is required and must be positive"), nil
+			}
+			if input.TargetGroupId <= 0 {
+				return toolset.NewToolResultError("target_group_id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			// Fetch the rule as a map so that fields not modeled in types.AlertRule are cloned too
+			rule, err := client.DoGet[map[string]any](c, ctx, fmt.Sprintf("/api/n9e/alert-rule/%d", input.RuleId), nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			for k := range alertRuleImmutableFields {
+				delete(rule, k)
+			}
+			rule["group_id"] = input.TargetGroupId
+			if input.Name != "" {
+				rule["name"] = input.Name
+			}
+			if input.Disabled != nil {
+				rule["disabled"] = 0
+				if *input.Disabled {
+					rule["disabled"] = 1
+				}
+			}
+			name, _ := rule["name"].(string)
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/alert-rules", input.TargetGroupId)
+			result, err := client.DoPost[map[string]string](c, ctx, path, []map[string]any{rule})
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+			if msg := result[name]; msg != "" {
+				return toolset.NewToolResultError(fmt.Sprintf("failed to clone alert rule %d: %s", input.RuleId, msg)), nil
+			}
+
+			return toolset.MarshalResult(CloneAlertRuleResult{
+				SourceId:      input.RuleId,
+				TargetGroupId: input.TargetGroupId,
+				Name:          name,
+				Message:       "Alert rule cloned successfully",
+			}), nil
+		}),
+	)
+}
+
+func setAlertRulesDisabledTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "set_alert_rules_disabled",
+			Description: "Enable or disable multiple alert rules in a business group at once",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Enable/Disable Alert Rules",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"group_id", "ids", "disabled"},
+				Properties: map[string]*jsonschema.Schema{
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID the rules belong to",
+					},
+					"ids": {
+						Type:        "array",
+						Description: "Alert rule IDs",
+						Items:       &jsonschema.Schema{Type: "integer"},
+					},
+					"disabled": {
+						Type:        "boolean",
+						Description: "true to disable, false to enable",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input SetAlertRulesDisabledInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("group_id is required and must be positive"), nil
+			}
+			if len(input.RuleIds) == 0 {
+				return toolset.NewToolResultError("ids is required"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			disabled := 0
+			if input.Disabled {
+				disabled = 1
+			}
+
+			body := map[string]any{
+				"ids":    input.RuleIds,
+				"fields": map[string]any{"disabled": disabled},
+			}
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/alert-rules/fields", input.GroupId)
+			if _, err := client.DoPut[any](c, ctx, path, body); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			action := "enabled"
+			if input.Disabled {
+				action = "disabled"
+			}
+			return toolset.MarshalResult(types.ActionResult{
+				Ids:     input.RuleIds,
+				Message: fmt.Sprintf("%d alert rule(s) %s successfully", len(input.RuleIds), action),
+			}), nil
+		}),
+	)
+}
+
+func deleteAlertRulesTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "delete_alert_rules",
+			Description: "Delete alert rules from a business group. This cannot be undone.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Delete Alert Rules",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(true),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"group_id", "ids"},
+				Properties: map[string]*jsonschema.Schema{
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID the rules belong to",
+					},
+					"ids": {
+						Type:        "array",
+						Description: "Alert rule IDs to delete",
+						Items:       &jsonschema.Schema{Type: "integer"},
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input DeleteAlertRulesInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("group_id is required and must be positive"), nil
+			}
+			if len(input.RuleIds) == 0 {
+				return toolset.NewToolResultError("ids is required"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/alert-rules", input.GroupId)
+			if _, err := client.DoDelete[any](c, ctx, path, map[string]any{"ids": input.RuleIds}); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Ids:     input.RuleIds,
+				Message: fmt.Sprintf("%d alert rule(s) deleted successfully", len(input.RuleIds)),
+			}), nil
+		}),
+	)
+}
+
+func claimActiveAlertTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "claim_active_alert",
+			Description: "Claim (acknowledge) an active alert event as the current user, or release a claim with unclaim=true",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Claim Active Alert",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"eid"},
+				Properties: map[string]*jsonschema.Schema{
+					"eid": {
+						Type:        "integer",
+						Description: "Active alert event ID",
+					},
+					"unclaim": {
+						Type:        "boolean",
+						Description: "Release the claim instead of claiming (default false)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[ClaimActiveAlertResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ClaimActiveAlertInput) (*mcp.CallToolResult, error) {
+			if input.EventId <= 0 {
+				return toolset.NewToolResultError("eid is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/alert-cur-event/%d/claim", input.EventId)
+			if _, err := client.DoPut[any](c, ctx, path, map[string]any{"claim": !input.Unclaim}); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			// Read back the event to report the resulting claimant
+			event, err := client.DoGet[types.AlertCurEvent](c, ctx, fmt.Sprintf("/api/n9e/alert-cur-event/%d", input.EventId), nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			message := "Alert event claimed successfully"
+			if input.Unclaim {
+				message = "Alert event claim released successfully"
+			}
+			return toolset.MarshalResult(ClaimActiveAlertResult{
+				EventId:  input.EventId,
+				Claimant: event.Claimant,
+				Message:  message,
+			}), nil
+		}),
+	)
+}
+
+func deleteActiveAlertsTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "delete_active_alerts",
+			Description: "Delete stale active alert events by ID. The events are removed from the active list without sending recovery notifications.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Delete Active Alerts",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(true),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"ids"},
+				Properties: map[string]*jsonschema.Schema{
+					"ids": {
+						Type:        "array",
+						Description: "Active alert event IDs to delete",
+						Items:       &jsonschema.Schema{Type: "integer"},
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input DeleteActiveAlertsInput) (*mcp.CallToolResult, error) {
+			if len(input.EventIds) == 0 {
+				return toolset.NewToolResultError("ids is required"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			if _, err := client.DoDelete[any](c, ctx, "/api/n9e/alert-cur-events", map[string]any{"ids": input.EventIds}); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Ids:     input.EventIds,
+				Message: fmt.Sprintf("%d active alert event(s) deleted successfully", len(input.EventIds)),
+			}), nil
+		}),
+	)
+}
+
+// listAllActiveAlerts lists active alert events matching params page by page,
+// truncated reports whether more than max events exist
+func listAllActiveAlerts(ctx context.Context, c *client.Client, params url.Values, max int) ([]types.AlertCurEvent, bool, error) {
+	var events []types.AlertCurEvent
+	for page := 1; ; page++ {
+		params.Set("limit", strconv.Itoa(activeAlertsPageSize))
+		params.Set("p", strconv.Itoa(page))
+
+		resp, err := client.DoGet[types.PageResp[types.AlertCurEvent]](c, ctx, "/api/n9e/alert-cur-events/list", params)
+		if err != nil {
+			return nil, false, err
+		}
+		events = append(events, resp.List...)
+
+		if len(resp.List) < activeAlertsPageSize || int64(len(events)) >= resp.Total {
+			return events, false, nil
+		}
+		if len(events) >= max {
+			return events, true, nil
+		}
+	}
+}
+
+func alertRuleResource(getClient client.GetClientFunc) toolset.ServerResourceTemplate {
+	return toolset.NewServerResourceTemplate(
+		mcp.ResourceTemplate{
+			URITemplate: "n9e://alert-rule/{id}",
+			Name:        "alert_rule",
+			Title:       "Alert Rule",
+			Description: "Alert rule definition by rule ID",
+			MIMEType:    "application/json",
+		},
+		makeResourceHandler(getClient, "n9e://alert-rule/", "", func(ctx context.Context, c *client.Client, id int64) (any, error) {
+			return client.DoGet[types.AlertRule](c, ctx, fmt.Sprintf("/api/n9e/alert-rule/%d", id), nil)
+		}),
+	)
+}
+
+func alertEventResource(getClient client.GetClientFunc) toolset.ServerResourceTemplate {
+	return toolset.NewServerResourceTemplate(
+		mcp.ResourceTemplate{
+			URITemplate: "n9e://alert-event/{eid}",
+			Name:        "alert_event",
+			Title:       "Alert Event",
+			Description: "Alert event by event ID, the active event if still firing, otherwise the historical event",
+			MIMEType:    "application/json",
+		},
+		makeResourceHandler(getClient, "n9e://alert-event/", "", func(ctx context.Context, c *client.Client, id int64) (any, error) {
+			// Active and historical events share IDs, fall back to history once the event is recovered
+			event, err := client.DoGet[types.AlertCurEvent](c, ctx, fmt.Sprintf("/api/n9e/alert-cur-event/%d", id), nil)
+			if err != nil && client.HTTPStatus(err) != http.StatusNotFound {
+				return nil, err
+			}
+			if err == nil && event.Id > 0 {
+				return event, nil
+			}
+			return client.DoGet[types.AlertHisEvent](c, ctx, fmt.Sprintf("/api/n9e/alert-his-event/%d", id), nil)
+		}),
+	)
+}
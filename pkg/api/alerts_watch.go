@@ -0,0 +1,312 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/n9e/n9e-mcp-server/pkg/client"
+	"github.com/n9e/n9e-mcp-server/pkg/toolset"
+	"github.com/n9e/n9e-mcp-server/pkg/types"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// DefaultAlertsPollInterval is the default interval between active alerts polls of a subscribed resource
+	DefaultAlertsPollInterval = 30 * time.Second
+	// DefaultAlertsPollMaxBackoff is the default longest delay between polls after consecutive failures
+	DefaultAlertsPollMaxBackoff = 5 * time.Minute
+
+	activeAlertsResourceScheme = "n9e"
+	activeAlertsResourceHost   = "alerts"
+	activeAlertsResourcePath   = "/active"
+	maxActiveAlertsResource    = 1000
+)
+
+// ActiveAlertsResource represents the contents of the n9e://alerts/active resource
+type ActiveAlertsResource struct {
+	BusiGroupId int64                 `json:"bgid,omitempty"`
+	Total       int                   `json:"total"`
+	Truncated   bool                  `json:"truncated"`
+	Events      []types.AlertCurEvent `json:"events"`
+}
+
+// parseActiveAlertsURI parses n9e://alerts/active[?bgid=N] into active alerts list query parameters
+func parseActiveAlertsURI(uri string) (url.Values, int64, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != activeAlertsResourceScheme || u.Host != activeAlertsResourceHost || u.Path != activeAlertsResourcePath {
+		return nil, 0, fmt.Errorf("invalid resource uri: %s", uri)
+	}
+
+	params := url.Values{}
+	var bgid int64
+	if v := u.Query().Get("bgid"); v != "" {
+		bgid, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || bgid <= 0 {
+			return nil, 0, fmt.Errorf("invalid bgid in resource uri: %s", uri)
+		}
+		params.Set("bgid", v)
+	}
+	return params, bgid, nil
+}
+
+func activeAlertsResource(getClient client.GetClientFunc) toolset.ServerResourceTemplate {
+	return toolset.NewServerResourceTemplate(
+		mcp.ResourceTemplate{
+			URITemplate: "n9e://alerts/active{?bgid}",
+			Name:        "active_alerts",
+			Title:       "Active Alerts",
+			Description: "Currently firing alerts, optionally of one business group. Subscribe to be notified when alerts start or stop firing.",
+			MIMEType:    "application/json",
+		},
+		func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			uri := req.Params.URI
+			params, bgid, err := parseActiveAlertsURI(uri)
+			if err != nil {
+				return nil, mcp.ResourceNotFoundError(uri)
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return nil, fmt.Errorf("failed to get n9e client from context")
+			}
+
+			events, truncated, err := listAllActiveAlerts(ctx, c, params, maxActiveAlertsResource)
+			if err != nil {
+				return nil, err
+			}
+			return toolset.MarshalResource(uri, ActiveAlertsResource{
+				BusiGroupId: bgid,
+				Total:       len(events),
+				Truncated:   truncated,
+				Events:      events,
+			})
+		},
+	)
+}
+
+// alertsPollerKey identifies a poller, sessions share a poller only when they use the same instance and token
+type alertsPollerKey struct {
+	identity string // client.Client.Identity
+	uri      string
+}
+
+// alertsPoller polls one subscribed resource on behalf of its sessions, with the client of each session
+type alertsPoller struct {
+	sessions map[*mcp.ServerSession]*client.Client
+	cancel   context.CancelFunc
+}
+
+// ActiveAlertsWatcher polls the active alerts of subscribed n9e://alerts/active resources in the background,
+// and sends notifications/resources/updated when alerts start or stop firing.
+// A poller runs per resource, instance and token while at least one session is subscribed, it polls with
+// the client of a session still subscribed. Resource updated notifications go to every session subscribed to
+// the URI, so changes seen by several pollers of the same URI within one poll interval are sent once.
+type ActiveAlertsWatcher struct {
+	getClient  client.GetClientFunc
+	interval   time.Duration
+	maxBackoff time.Duration
+
+	mu       sync.Mutex
+	server   *mcp.Server
+	pollers  map[alertsPollerKey]*alertsPoller
+	notified map[string]time.Time
+}
+
+// NewActiveAlertsWatcher creates an active alerts watcher, zero durations use the defaults
+func NewActiveAlertsWatcher(getClient client.GetClientFunc, interval, maxBackoff time.Duration) *ActiveAlertsWatcher {
+	if interval <= 0 {
+		interval = DefaultAlertsPollInterval
+	}
+	if maxBackoff < interval {
+		maxBackoff = max(DefaultAlertsPollMaxBackoff, interval)
+	}
+	return &ActiveAlertsWatcher{
+		getClient:  getClient,
+		interval:   interval,
+		maxBackoff: maxBackoff,
+		pollers:    make(map[alertsPollerKey]*alertsPoller),
+		notified:   make(map[string]time.Time),
+	}
+}
+
+// SetServer sets the MCP server used to send resource updated notifications
+func (w *ActiveAlertsWatcher) SetServer(s *mcp.Server) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.server = s
+}
+
+// Subscribe is the MCP subscribe handler, it starts polling the resource if not polled yet.
+// The resource is fetched once with the session's token, authentication and permission errors
+// are returned to the caller.
+func (w *ActiveAlertsWatcher) Subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	uri := req.Params.URI
+	params, _, err := parseActiveAlertsURI(uri)
+	if err != nil {
+		return fmt.Errorf("resource %s does not support subscriptions", uri)
+	}
+
+	c := w.getClient(ctx)
+	if c == nil {
+		return fmt.Errorf("failed to get n9e client from context")
+	}
+
+	var baseline map[string]bool
+	events, _, err := listAllActiveAlerts(ctx, c, params, maxActiveAlertsResource)
+	if err == nil {
+		baseline = alertHashes(events)
+	} else if status := client.HTTPStatus(err); status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return err
+	} else {
+		// Other failures are retried by the poller
+		slog.Warn("failed to fetch active alerts on subscribe", "uri", uri, "error", err)
+	}
+
+	key := alertsPollerKey{identity: c.Identity(), uri: uri}
+	session := req.Session
+
+	w.mu.Lock()
+	p, ok := w.pollers[key]
+	if !ok {
+		pollCtx, cancel := context.WithCancel(context.Background())
+		p = &alertsPoller{sessions: make(map[*mcp.ServerSession]*client.Client), cancel: cancel}
+		w.pollers[key] = p
+		go w.poll(pollCtx, p, uri, params, baseline)
+	}
+	_, alreadySubscribed := p.sessions[session]
+	p.sessions[session] = c
+	w.mu.Unlock()
+
+	// Sessions may go away without unsubscribing
+	if !alreadySubscribed && session != nil {
+		go func() {
+			session.Wait()
+			w.remove(session, func(string) bool { return true })
+		}()
+	}
+	return nil
+}
+
+// Unsubscribe is the MCP unsubscribe handler, it stops polling the resource once no session is subscribed
+func (w *ActiveAlertsWatcher) Unsubscribe(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	uri := req.Params.URI
+	w.remove(req.Session, func(u string) bool { return u == uri })
+	return nil
+}
+
+// remove unsubscribes session from the pollers selected by match, and stops pollers left without sessions
+func (w *ActiveAlertsWatcher) remove(session *mcp.ServerSession, match func(uri string) bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, p := range w.pollers {
+		if _, ok := p.sessions[session]; !ok || !match(key.uri) {
+			continue
+		}
+		delete(p.sessions, session)
+		if len(p.sessions) == 0 {
+			p.cancel()
+			delete(w.pollers, key)
+		}
+	}
+}
+
+// pollClient returns the client of a session still subscribed to p, nil once all sessions left
+func (w *ActiveAlertsWatcher) pollClient(p *alertsPoller) *client.Client {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, c := range p.sessions {
+		return c
+	}
+	return nil
+}
+
+// poll lists active alerts periodically and notifies subscribers when the set of alert hashes changes.
+// last is the baseline fetched on subscribe, nil when unknown.
+// Failed polls are retried with exponential backoff up to maxBackoff.
+func (w *ActiveAlertsWatcher) poll(ctx context.Context, p *alertsPoller, uri string, params url.Values, last map[string]bool) {
+	delay := w.interval
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		c := w.pollClient(p)
+		if c == nil {
+			return
+		}
+		events, truncated, err := listAllActiveAlerts(ctx, c, params, maxActiveAlertsResource)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			delay = min(delay*2, w.maxBackoff)
+			slog.Warn("failed to poll active alerts", "uri", uri, "error", err, "retry_in", delay)
+			continue
+		}
+
+		delay = w.interval
+		if truncated {
+			slog.Warn("active alerts resource truncated", "uri", uri, "max", maxActiveAlertsResource)
+		}
+
+		current := alertHashes(events)
+		// Without a baseline the first successful poll only records it
+		if last != nil && !sameHashes(last, current) {
+			w.notify(ctx, uri)
+		}
+		last = current
+	}
+}
+
+// alertHashes returns the set of hashes of events
+func alertHashes(events []types.AlertCurEvent) map[string]bool {
+	hashes := make(map[string]bool, len(events))
+	for _, e := range events {
+		hashes[e.Hash] = true
+	}
+	return hashes
+}
+
+// notify sends notifications/resources/updated to all sessions subscribed to uri,
+// unless another poller of uri already did within the poll interval
+func (w *ActiveAlertsWatcher) notify(ctx context.Context, uri string) {
+	w.mu.Lock()
+	s := w.server
+	now := time.Now()
+	if last, ok := w.notified[uri]; ok && now.Sub(last) < w.interval {
+		w.mu.Unlock()
+		return
+	}
+	w.notified[uri] = now
+	w.mu.Unlock()
+	if s == nil {
+		return
+	}
+	if err := s.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+		slog.Warn("failed to send resource updated notification", "uri", uri, "error", err)
+	}
+}
+
+// sameHashes reports whether two alert hash sets are equal
+func sameHashes(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for h := range a {
+		if !b[h] {
+			return false
+		}
+	}
+	return true
+}
@@ -2,6 +2,11 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/n9e/n9e-mcp-server/pkg/client"
 	"github.com/n9e/n9e-mcp-server/pkg/toolset"
@@ -11,12 +16,75 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// GetBusiGroupInput represents get business group parameters
+type GetBusiGroupInput struct {
+	GroupId int64 `json:"id"`
+}
+
+// BusiGroupMemberInput represents a member user group with its permission
+type BusiGroupMemberInput struct {
+	UserGroupId int64  `json:"user_group_id"`
+	PermFlag    string `json:"perm_flag"`
+}
+
+// CreateBusiGroupInput represents create business group parameters
+type CreateBusiGroupInput struct {
+	Name        string                 `json:"name"`
+	LabelEnable int                    `json:"label_enable,omitempty"`
+	LabelValue  string                 `json:"label_value,omitempty"`
+	Members     []BusiGroupMemberInput `json:"members"`
+}
+
+// UpdateBusiGroupInput represents update business group parameters, omitted fields are unchanged
+type UpdateBusiGroupInput struct {
+	GroupId     int64   `json:"id"`
+	Name        *string `json:"name,omitempty"`
+	LabelEnable *int    `json:"label_enable,omitempty"`
+	LabelValue  *string `json:"label_value,omitempty"`
+}
+
+// AddBusiGroupMembersInput represents add business group members parameters
+type AddBusiGroupMembersInput struct {
+	GroupId int64                  `json:"id"`
+	Members []BusiGroupMemberInput `json:"members"`
+}
+
+// RemoveBusiGroupMembersInput represents remove business group members parameters
+type RemoveBusiGroupMembersInput struct {
+	GroupId      int64   `json:"id"`
+	UserGroupIds []int64 `json:"user_group_ids"`
+}
+
+// BusiGroupDetail represents a business group with its members and object counts
+type BusiGroupDetail struct {
+	types.BusiGroup
+	RuleCount   int      `json:"rule_count"`
+	TargetCount int64    `json:"target_count"`
+	MuteCount   int      `json:"mute_count"`
+	CountErrors []string `json:"count_errors,omitempty"`
+}
+
+// validPermFlags are the permissions a user group can have on a business group
+var validPermFlags = map[string]bool{"rw": true, "ro": true}
+
 // RegisterBusiGroupsToolset registers business groups toolset
 func RegisterBusiGroupsToolset(group *toolset.ToolsetGroup, getClient client.GetClientFunc) {
 	ts := toolset.NewToolset("busi_groups", "Business group management tools")
 
 	ts.AddReadTools(
 		listBusiGroupsTool(getClient),
+		getBusiGroupTool(getClient),
+	)
+
+	ts.AddWriteTools(
+		createBusiGroupTool(getClient),
+		updateBusiGroupTool(getClient),
+		addBusiGroupMembersTool(getClient),
+		removeBusiGroupMembersTool(getClient),
+	)
+
+	ts.AddResourceTemplates(
+		busiGroupResource(getClient),
 	)
 
 	group.AddToolset(ts)
@@ -35,6 +103,7 @@ func listBusiGroupsTool(getClient client.GetClientFunc) toolset.ServerTool {
 				Type:       "object",
 				Properties: map[string]*jsonschema.Schema{},
 			},
+			OutputSchema: toolset.OutputSchema[[]types.BusiGroup](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, error) {
 			c := getClient(ctx)
@@ -51,3 +120,418 @@ func listBusiGroupsTool(getClient client.GetClientFunc) toolset.ServerTool {
 		}),
 	)
 }
+
+func getBusiGroupTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "get_busi_group",
+			Description: "Get a business group with its member teams (user groups and their rw/ro permission) and counts of alert rules, targets and mutes",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Business Group",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Business group ID",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[BusiGroupDetail](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetBusiGroupInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			bg, err := client.DoGet[types.BusiGroup](c, ctx, fmt.Sprintf("/api/n9e/busi-group/%d", input.GroupId), nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			detail := BusiGroupDetail{BusiGroup: bg}
+
+			// Counts are best effort, a failed count is reported instead of failing the whole call
+			var mu sync.Mutex
+			var wg sync.WaitGroup
+			countErr := func(what string, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				detail.CountErrors = append(detail.CountErrors, fmt.Sprintf("%s: %v", what, err))
+			}
+
+			wg.Add(3)
+			go func() {
+				defer wg.Done()
+				rules, err := client.DoGet[[]types.AlertRule](c, ctx, fmt.Sprintf("/api/n9e/busi-group/%d/alert-rules", input.GroupId), nil)
+				if err != nil {
+					countErr("rules", err)
+					return
+				}
+				detail.RuleCount = len(rules)
+			}()
+			go func() {
+				defer wg.Done()
+				params := url.Values{}
+				params.Set("gids", strconv.FormatInt(input.GroupId, 10))
+				params.Set("limit", "1")
+				targets, err := client.DoGet[types.PageResp[types.Target]](c, ctx, "/api/n9e/targets", params)
+				if err != nil {
+					countErr("targets", err)
+					return
+				}
+				detail.TargetCount = targets.Total
+			}()
+			go func() {
+				defer wg.Done()
+				mutes, err := client.DoGet[[]types.AlertMute](c, ctx, fmt.Sprintf("/api/n9e/busi-group/%d/alert-mutes", input.GroupId), nil)
+				if err != nil {
+					countErr("mutes", err)
+					return
+				}
+				detail.MuteCount = len(mutes)
+			}()
+			wg.Wait()
+
+			return toolset.MarshalResult(detail), nil
+		}),
+	)
+}
+
+// busiGroupMembersSchema describes the member user groups parameter
+func busiGroupMembersSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "array",
+		Description: "Member user groups (teams) and their permission",
+		Items: &jsonschema.Schema{
+			Type:     "object",
+			Required: []string{"user_group_id", "perm_flag"},
+			Properties: map[string]*jsonschema.Schema{
+				"user_group_id": {Type: "integer", Description: "User group ID"},
+				"perm_flag":     {Type: "string", Description: "Permission: rw (read-write) or ro (read-only)", Enum: []any{"rw", "ro"}},
+			},
+		},
+	}
+}
+
+// busiGroupMembers validates members and converts them to the API format
+func busiGroupMembers(groupId int64, members []BusiGroupMemberInput) ([]types.BusiGroupMember, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("members is required")
+	}
+
+	result := make([]types.BusiGroupMember, 0, len(members))
+	for _, m := range members {
+		if m.UserGroupId <= 0 {
+			return nil, fmt.Errorf("user_group_id must be positive")
+		}
+		if !validPermFlags[m.PermFlag] {
+			return nil, fmt.Errorf("invalid perm_flag: %s, valid values: rw, ro", m.PermFlag)
+		}
+		result = append(result, types.BusiGroupMember{
+			BusiGroupId: groupId,
+			UserGroupId: m.UserGroupId,
+			PermFlag:    m.PermFlag,
+		})
+	}
+	return result, nil
+}
+
+// validateBusiGroupLabel validates label_enable and label_value
+func validateBusiGroupLabel(labelEnable int, labelValue string) error {
+	if labelEnable != 0 && labelEnable != 1 {
+		return fmt.Errorf("invalid label_enable: %d, must be 0 or 1", labelEnable)
+	}
+	if labelEnable == 1 && strings.TrimSpace(labelValue) == "" {
+		return fmt.Errorf("label_value is required when label_enable is 1")
+	}
+	return nil
+}
+
+func createBusiGroupTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "create_busi_group",
+			Description: "Create a new business group with at least one member user group (team)",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Create Business Group",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"name", "members"},
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Business group name, may use '-' to form a tree, e.g. infra-db-mysql",
+					},
+					"label_enable": {
+						Type:        "integer",
+						Description: "Attach the busigroup label to targets of this group (0=no, 1=yes)",
+					},
+					"label_value": {
+						Type:        "string",
+						Description: "Value of the busigroup label (required when label_enable=1)",
+					},
+					"members": busiGroupMembersSchema(),
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input CreateBusiGroupInput) (*mcp.CallToolResult, error) {
+			if strings.TrimSpace(input.Name) == "" {
+				return toolset.NewToolResultError("name is required"), nil
+			}
+			if err := validateBusiGroupLabel(input.LabelEnable, input.LabelValue); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+			members, err := busiGroupMembers(0, input.Members)
+			if err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			body := map[string]any{
+				"name":         strings.TrimSpace(input.Name),
+				"label_enable": input.LabelEnable,
+				"label_value":  input.LabelValue,
+				"members":      members,
+			}
+			result, err := client.DoPost[int64](c, ctx, "/api/n9e/busi-groups", body)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      result,
+				Message: "Business group created successfully",
+			}), nil
+		}),
+	)
+}
+
+func updateBusiGroupTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "update_busi_group",
+			Description: "Rename a business group or change its label settings. Omitted fields are left unchanged.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Update Business Group",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Business group ID",
+					},
+					"name": {
+						Type:        "string",
+						Description: "New business group name",
+					},
+					"label_enable": {
+						Type:        "integer",
+						Description: "Attach the busigroup label to targets of this group (0=no, 1=yes)",
+					},
+					"label_value": {
+						Type:        "string",
+						Description: "Value of the busigroup label (required when label_enable=1)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UpdateBusiGroupInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+			if input.Name == nil && input.LabelEnable == nil && input.LabelValue == nil {
+				return toolset.NewToolResultError("at least one of name, label_enable and label_value is required"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			// The update API replaces all fields, start from the current values
+			path := fmt.Sprintf("/api/n9e/busi-group/%d", input.GroupId)
+			bg, err := client.DoGet[types.BusiGroup](c, ctx, path, nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+			if input.Name != nil {
+				bg.Name = strings.TrimSpace(*input.Name)
+			}
+			if input.LabelEnable != nil {
+				bg.LabelEnable = *input.LabelEnable
+			}
+			if input.LabelValue != nil {
+				bg.LabelValue = *input.LabelValue
+			}
+
+			if bg.Name == "" {
+				return toolset.NewToolResultError("name cannot be empty"), nil
+			}
+			if err := validateBusiGroupLabel(bg.LabelEnable, bg.LabelValue); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			body := map[string]any{
+				"name":         bg.Name,
+				"label_enable": bg.LabelEnable,
+				"label_value":  bg.LabelValue,
+			}
+			if _, err := client.DoPut[any](c, ctx, path, body); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      input.GroupId,
+				Message: "Business group updated successfully",
+			}), nil
+		}),
+	)
+}
+
+func addBusiGroupMembersTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "add_busi_group_members",
+			Description: "Grant user groups (teams) rw or ro permission on a business group",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Add Business Group Members",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id", "members"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Business group ID",
+					},
+					"members": busiGroupMembersSchema(),
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input AddBusiGroupMembersInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+			members, err := busiGroupMembers(input.GroupId, input.Members)
+			if err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/members", input.GroupId)
+			if _, err := client.DoPost[any](c, ctx, path, members); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      input.GroupId,
+				Message: fmt.Sprintf("%d member(s) added successfully", len(members)),
+			}), nil
+		}),
+	)
+}
+
+func removeBusiGroupMembersTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "remove_busi_group_members",
+			Description: "Revoke user groups' (teams') permission on a business group",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Remove Business Group Members",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(true),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id", "user_group_ids"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Business group ID",
+					},
+					"user_group_ids": {
+						Type:        "array",
+						Description: "User group IDs to remove",
+						Items:       &jsonschema.Schema{Type: "integer"},
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input RemoveBusiGroupMembersInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+			if len(input.UserGroupIds) == 0 {
+				return toolset.NewToolResultError("user_group_ids is required"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			members := make([]types.BusiGroupMember, 0, len(input.UserGroupIds))
+			for _, id := range input.UserGroupIds {
+				members = append(members, types.BusiGroupMember{BusiGroupId: input.GroupId, UserGroupId: id})
+			}
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/members", input.GroupId)
+			if _, err := client.DoDelete[any](c, ctx, path, members); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      input.GroupId,
+				Message: fmt.Sprintf("%d member(s) removed successfully", len(members)),
+			}), nil
+		}),
+	)
+}
+
+func busiGroupResource(getClient client.GetClientFunc) toolset.ServerResourceTemplate {
+	return toolset.NewServerResourceTemplate(
+		mcp.ResourceTemplate{
+			URITemplate: "n9e://busi-group/{id}",
+			Name:        "busi_group",
+			Title:       "Business Group",
+			Description: "Business group details and member teams by group ID",
+			MIMEType:    "application/json",
+		},
+		makeResourceHandler(getClient, "n9e://busi-group/", "", func(ctx context.Context, c *client.Client, id int64) (any, error) {
+			return client.DoGet[types.BusiGroup](c, ctx, fmt.Sprintf("/api/n9e/busi-group/%d", id), nil)
+		}),
+	)
+}
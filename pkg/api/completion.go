@@ -0,0 +1,234 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/n9e/n9e-mcp-server/pkg/client"
+	"github.com/n9e/n9e-mcp-server/pkg/toolset"
+	"github.com/n9e/n9e-mcp-server/pkg/types"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// completionCacheTTL is how long completion lookups are cached per session
+	completionCacheTTL = time.Minute
+	// maxCompletionValues is the maximum number of completion values, as defined by MCP
+	maxCompletionValues = 100
+)
+
+// completionItem is a completion candidate, matched by its value or label
+type completionItem struct {
+	Value string
+	Label string
+}
+
+// completionSource lists the candidates of an argument, args holds the other arguments already filled in.
+// Candidates are cached per session under the key returned by cacheKey.
+type completionSource struct {
+	cacheKey func(args map[string]string) string
+	fetch    func(ctx context.Context, c *client.Client, args map[string]string) ([]completionItem, error)
+}
+
+// completionCacheEntry is a cached candidate list
+type completionCacheEntry struct {
+	items   []completionItem
+	expires time.Time
+}
+
+// Completer handles MCP completion requests for prompt and resource template arguments.
+// Business group IDs, datasource IDs, alert rules of the business group in bgid and enum values are suggested
+// by argument name, lookups are cached per session.
+type Completer struct {
+	getClient client.GetClientFunc
+
+	mu    sync.Mutex
+	cache map[*mcp.ServerSession]map[string]completionCacheEntry
+}
+
+// NewCompleter creates a Completer
+func NewCompleter(getClient client.GetClientFunc) *Completer {
+	return &Completer{
+		getClient: getClient,
+		cache:     make(map[*mcp.ServerSession]map[string]completionCacheEntry),
+	}
+}
+
+// Complete is the MCP completion handler
+func (cp *Completer) Complete(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	result := &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: []string{}}}
+	params := req.Params
+	if params == nil || params.Ref == nil {
+		return result, nil
+	}
+
+	source, ok := completionSourceFor(params.Ref, params.Argument.Name)
+	if !ok {
+		return result, nil
+	}
+
+	c := cp.getClient(ctx)
+	if c == nil {
+		return nil, fmt.Errorf("failed to get n9e client from context")
+	}
+
+	var args map[string]string
+	if params.Context != nil {
+		args = params.Context.Arguments
+	}
+	items, err := cp.lookup(ctx, req.Session, c, args, source)
+	if err != nil {
+		return nil, err
+	}
+
+	value := strings.ToLower(strings.TrimSpace(params.Argument.Value))
+
+	for _, item := range items {
+		if value != "" && !strings.HasPrefix(strings.ToLower(item.Value), value) && !strings.Contains(strings.ToLower(item.Label), value) {
+			continue
+		}
+		result.Completion.Total++
+		if len(result.Completion.Values) < maxCompletionValues {
+			result.Completion.Values = append(result.Completion.Values, item.Value)
+		}
+	}
+	result.Completion.HasMore = result.Completion.Total > len(result.Completion.Values)
+	return result, nil
+}
+
+// lookup returns the candidates of source, from the session cache when fresh
+func (cp *Completer) lookup(ctx context.Context, session *mcp.ServerSession, c *client.Client, args map[string]string, source completionSource) ([]completionItem, error) {
+	key := source.cacheKey(args)
+
+	cp.mu.Lock()
+	entry, ok := cp.cache[session][key]
+	cp.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.items, nil
+	}
+
+	items, err := source.fetch(ctx, c, args)
+	if err != nil {
+		return nil, err
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	sessionCache, ok := cp.cache[session]
+	if !ok {
+		sessionCache = make(map[string]completionCacheEntry)
+		cp.cache[session] = sessionCache
+		// Drop the session cache once the session ends
+		if session != nil {
+			go func() {
+				session.Wait()
+				cp.mu.Lock()
+				delete(cp.cache, session)
+				cp.mu.Unlock()
+			}()
+		}
+	}
+	sessionCache[key] = completionCacheEntry{items: items, expires: time.Now().Add(completionCacheTTL)}
+	return items, nil
+}
+
+// completionSourceFor selects the candidate source of an argument by its name.
+// The {id} of business group resource templates is completed as a business group ID.
+func completionSourceFor(ref *mcp.CompleteReference, argument string) (completionSource, bool) {
+	if ref.Type == "ref/resource" && argument == "id" && strings.HasPrefix(ref.URI, "n9e://busi-group/") {
+		return busiGroupCompletion, true
+	}
+
+	switch argument {
+	case "bgid":
+		return busiGroupCompletion, true
+	case "datasource_id":
+		return datasourceCompletion, true
+	case "rule_id":
+		return alertRuleCompletion, true
+	case "cate":
+		return enumCompletion("cate", toolset.ValidCates), true
+	case "prod":
+		return enumCompletion("prod", toolset.ValidRuleProds), true
+	}
+	return completionSource{}, false
+}
+
+// fixedKey returns a cache key function ignoring the arguments
+func fixedKey(key string) func(map[string]string) string {
+	return func(map[string]string) string { return key }
+}
+
+var busiGroupCompletion = completionSource{
+	cacheKey: fixedKey("busi_groups"),
+	fetch: func(ctx context.Context, c *client.Client, args map[string]string) ([]completionItem, error) {
+		groups, err := client.DoGet[[]types.BusiGroup](c, ctx, "/api/n9e/busi-groups", nil)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]completionItem, 0, len(groups))
+		for _, bg := range groups {
+			items = append(items, completionItem{Value: strconv.FormatInt(bg.Id, 10), Label: bg.Name})
+		}
+		return items, nil
+	},
+}
+
+var datasourceCompletion = completionSource{
+	cacheKey: fixedKey("datasources"),
+	fetch: func(ctx context.Context, c *client.Client, args map[string]string) ([]completionItem, error) {
+		list, err := client.DoGet[[]types.Datasource](c, ctx, "/api/n9e/datasource/brief", nil)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]completionItem, 0, len(list))
+		for _, ds := range list {
+			items = append(items, completionItem{Value: strconv.FormatInt(ds.Id, 10), Label: ds.Name + " " + ds.PluginType})
+		}
+		return items, nil
+	},
+}
+
+// alertRuleCompletion completes rule IDs, matched by ID or name, in the business group given by the bgid argument
+var alertRuleCompletion = completionSource{
+	cacheKey: func(args map[string]string) string {
+		return "alert_rules:" + args["bgid"]
+	},
+	fetch: func(ctx context.Context, c *client.Client, args map[string]string) ([]completionItem, error) {
+		bgid, err := strconv.ParseInt(args["bgid"], 10, 64)
+		if err != nil || bgid <= 0 {
+			return nil, nil
+		}
+
+		rules, err := client.DoGet[[]types.AlertRule](c, ctx, fmt.Sprintf("/api/n9e/busi-group/%d/alert-rules", bgid), nil)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]completionItem, 0, len(rules))
+		for _, r := range rules {
+			items = append(items, completionItem{Value: strconv.FormatInt(r.Id, 10), Label: r.Name})
+		}
+		return items, nil
+	},
+}
+
+// enumCompletion completes the keys of an enumeration, sorted
+func enumCompletion(name string, values map[string]bool) completionSource {
+	return completionSource{
+		cacheKey: fixedKey("enum:" + name),
+		fetch: func(ctx context.Context, c *client.Client, args map[string]string) ([]completionItem, error) {
+			items := make([]completionItem, 0, len(values))
+			for v := range values {
+				items = append(items, completionItem{Value: v})
+			}
+			sort.Slice(items, func(i, j int) bool { return items[i].Value < items[j].Value })
+			return items, nil
+		},
+	}
+}
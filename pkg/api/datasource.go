@@ -2,6 +2,15 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/n9e/n9e-mcp-server/pkg/client"
 	"github.com/n9e/n9e-mcp-server/pkg/toolset"
@@ -11,12 +20,136 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+const (
+	defaultQueryMaxSeries = 50
+	defaultQueryMaxPoints = 60
+	maxQueryPoints        = 300
+	minQueryStep          = 15
+
+	defaultDiscoveryLimit = 100
+	maxDiscoveryLimit     = 1000
+
+	defaultLogLimit       = 100
+	maxLogLimit           = 1000
+	maxLogLineLength      = 2000
+	defaultESTimeField    = "@timestamp"
+	defaultESMessageField = "message"
+)
+
+// labelNameRegexp matches valid Prometheus label names
+var labelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// QueryInstantInput represents PromQL instant query parameters
+type QueryInstantInput struct {
+	DatasourceId int64  `json:"datasource_id"`
+	Query        string `json:"query"`
+	Time         int64  `json:"time,omitempty"`
+	MaxSeries    int    `json:"max_series,omitempty"`
+}
+
+// QueryRangeInput represents PromQL range query parameters
+type QueryRangeInput struct {
+	DatasourceId int64  `json:"datasource_id"`
+	Query        string `json:"query"`
+	Hours        int64  `json:"hours,omitempty"`
+	Stime        int64  `json:"stime,omitempty"`
+	Etime        int64  `json:"etime,omitempty"`
+	Step         int64  `json:"step,omitempty"`
+	MaxSeries    int    `json:"max_series,omitempty"`
+}
+
+// ListMetricNamesInput represents metric names discovery parameters
+type ListMetricNamesInput struct {
+	DatasourceId int64  `json:"datasource_id"`
+	Pattern      string `json:"pattern,omitempty"`
+	Hours        int64  `json:"hours,omitempty"`
+	Limit        int    `json:"limit,omitempty"`
+}
+
+// ListLabelNamesInput represents label names discovery parameters
+type ListLabelNamesInput struct {
+	DatasourceId int64  `json:"datasource_id"`
+	Metric       string `json:"metric,omitempty"`
+	Hours        int64  `json:"hours,omitempty"`
+	Limit        int    `json:"limit,omitempty"`
+}
+
+// ListLabelValuesInput represents label values discovery parameters
+type ListLabelValuesInput struct {
+	DatasourceId int64  `json:"datasource_id"`
+	Label        string `json:"label"`
+	Match        string `json:"match,omitempty"`
+	Hours        int64  `json:"hours,omitempty"`
+	Limit        int    `json:"limit,omitempty"`
+}
+
+// DiscoveryResult represents a capped list of metric names, label names or label values
+type DiscoveryResult struct {
+	Values    []string `json:"values"`
+	Total     int      `json:"total"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+// QueryLogsInput represents log search parameters
+type QueryLogsInput struct {
+	DatasourceId int64  `json:"datasource_id"`
+	Query        string `json:"query"`
+	Index        string `json:"index,omitempty"`
+	TimeField    string `json:"time_field,omitempty"`
+	Hours        int64  `json:"hours,omitempty"`
+	Stime        int64  `json:"stime,omitempty"`
+	Etime        int64  `json:"etime,omitempty"`
+	Limit        int    `json:"limit,omitempty"`
+}
+
+// LogLine represents a normalized log line
+type LogLine struct {
+	Timestamp string            `json:"timestamp"`
+	Line      string            `json:"line"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// LogQueryResult represents normalized log search result, newest first
+type LogQueryResult struct {
+	DatasourceType string    `json:"datasource_type"`
+	Lines          []LogLine `json:"lines"`
+	Total          int64     `json:"total,omitempty"` // Total matching lines, only known for Elasticsearch
+	Truncated      bool      `json:"truncated,omitempty"`
+}
+
+// QuerySeries represents a compact query result series
+type QuerySeries struct {
+	Labels    map[string]string `json:"labels"`
+	Value     string            `json:"value,omitempty"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Values    [][2]any          `json:"values,omitempty"`
+	Min       *float64          `json:"min,omitempty"`
+	Max       *float64          `json:"max,omitempty"`
+	Last      string            `json:"last,omitempty"`
+}
+
+// QueryResult represents a compact PromQL query result
+type QueryResult struct {
+	ResultType  string        `json:"result_type"`
+	Series      []QuerySeries `json:"series"`
+	TotalSeries int           `json:"total_series"`
+	Truncated   bool          `json:"truncated,omitempty"`
+	Step        int64         `json:"step,omitempty"`
+	Warnings    []string      `json:"warnings,omitempty"`
+}
+
 // RegisterDatasourceToolset registers datasource toolset
 func RegisterDatasourceToolset(group *toolset.ToolsetGroup, getClient client.GetClientFunc) {
 	ts := toolset.NewToolset("datasource", "Datasource management tools")
 
 	ts.AddReadTools(
 		listDatasourcesTool(getClient),
+		queryInstantTool(getClient),
+		queryRangeTool(getClient),
+		listMetricNamesTool(getClient),
+		listLabelNamesTool(getClient),
+		listLabelValuesTool(getClient),
+		queryLogsTool(getClient),
 	)
 
 	group.AddToolset(ts)
@@ -35,6 +168,7 @@ func listDatasourcesTool(getClient client.GetClientFunc) toolset.ServerTool {
 				Type:       "object",
 				Properties: map[string]*jsonschema.Schema{},
 			},
+			OutputSchema: toolset.OutputSchema[[]types.Datasource](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, error) {
 			c := getClient(ctx)
@@ -51,3 +185,785 @@ func listDatasourcesTool(getClient client.GetClientFunc) toolset.ServerTool {
 		}),
 	)
 }
+
+// datasourceProxyPath builds the Nightingale datasource proxy path
+func datasourceProxyPath(datasourceId int64, apiPath string) string {
+	return fmt.Sprintf("/api/n9e/proxy/%d%s", datasourceId, apiPath)
+}
+
+func queryInstantTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "query_instant",
+			Description: "Evaluate a PromQL expression at a single point in time against a Prometheus-compatible datasource. Use this to check the current value behind an alert's prom_ql.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "PromQL Instant Query",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"datasource_id", "query"},
+				Properties: map[string]*jsonschema.Schema{
+					"datasource_id": {
+						Type:        "integer",
+						Description: "Datasource ID (see list_datasources, or datasource_id of an alert event)",
+					},
+					"query": {
+						Type:        "string",
+						Description: "PromQL expression",
+					},
+					"time": {
+						Type:        "integer",
+						Description: "Evaluation time Unix timestamp (default now)",
+					},
+					"max_series": {
+						Type:        "integer",
+						Description: "Maximum number of series to return (default 50)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[QueryResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input QueryInstantInput) (*mcp.CallToolResult, error) {
+			if input.DatasourceId <= 0 {
+				return toolset.NewToolResultError("datasource_id is required and must be positive"), nil
+			}
+			if input.Query == "" {
+				return toolset.NewToolResultError("query is required"), nil
+			}
+			if input.MaxSeries < 0 {
+				return toolset.NewToolResultError("max_series must be >= 0"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			params := url.Values{}
+			params.Set("query", input.Query)
+			if input.Time > 0 {
+				params.Set("time", strconv.FormatInt(input.Time, 10))
+			}
+
+			resp, err := client.DoGetRaw[types.PromResponse[types.PromQueryData]](c, ctx, datasourceProxyPath(input.DatasourceId, "/api/v1/query"), params)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			result, err := compactQueryResult(resp, input.MaxSeries)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+func queryRangeTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "query_range",
+			Description: "Evaluate a PromQL expression over a time range against a Prometheus-compatible datasource. Returns per-series points with min/max/last.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "PromQL Range Query",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"datasource_id", "query"},
+				Properties: map[string]*jsonschema.Schema{
+					"datasource_id": {
+						Type:        "integer",
+						Description: "Datasource ID (see list_datasources, or datasource_id of an alert event)",
+					},
+					"query": {
+						Type:        "string",
+						Description: "PromQL expression",
+					},
+					"hours": {
+						Type:        "integer",
+						Description: "Lookback hours from now (mutually exclusive with stime/etime, default 1)",
+					},
+					"stime": {
+						Type:        "integer",
+						Description: "Start time Unix timestamp",
+					},
+					"etime": {
+						Type:        "integer",
+						Description: "End time Unix timestamp (default now)",
+					},
+					"step": {
+						Type:        "integer",
+						Description: "Query resolution step in seconds (default: range split into ~60 points, min 15). Raised if the range would exceed 300 points per series",
+					},
+					"max_series": {
+						Type:        "integer",
+						Description: "Maximum number of series to return (default 50)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[QueryResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input QueryRangeInput) (*mcp.CallToolResult, error) {
+			if input.DatasourceId <= 0 {
+				return toolset.NewToolResultError("datasource_id is required and must be positive"), nil
+			}
+			if input.Query == "" {
+				return toolset.NewToolResultError("query is required"), nil
+			}
+			if err := toolset.ValidateTimeRange(input.Hours, input.Stime, input.Etime); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+			if input.Step < 0 || input.MaxSeries < 0 {
+				return toolset.NewToolResultError("step and max_series must be >= 0"), nil
+			}
+
+			stime, etime := resolveTimeRange(input.Hours, input.Stime, input.Etime, 1)
+			if stime >= etime {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: stime (%d) must be less than etime (%d)", stime, etime)), nil
+			}
+			step := input.Step
+			if step == 0 {
+				step = int64(math.Ceil(float64(etime-stime) / defaultQueryMaxPoints))
+				if step < minQueryStep {
+					step = minQueryStep
+				}
+			}
+			// Cap the points per series, a small step over a long range is coarsened
+			if minStep := int64(math.Ceil(float64(etime-stime) / maxQueryPoints)); step < minStep {
+				step = minStep
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			params := url.Values{}
+			params.Set("query", input.Query)
+			params.Set("start", strconv.FormatInt(stime, 10))
+			params.Set("end", strconv.FormatInt(etime, 10))
+			params.Set("step", strconv.FormatInt(step, 10))
+
+			resp, err := client.DoGetRaw[types.PromResponse[types.PromQueryData]](c, ctx, datasourceProxyPath(input.DatasourceId, "/api/v1/query_range"), params)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			result, err := compactQueryResult(resp, input.MaxSeries)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+			result.Step = step
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+func listMetricNamesTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "list_metric_names",
+			Description: "List metric names of a Prometheus-compatible datasource, optionally filtered by a regex pattern",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Metric Names",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"datasource_id"},
+				Properties: map[string]*jsonschema.Schema{
+					"datasource_id": {
+						Type:        "integer",
+						Description: "Datasource ID (see list_datasources)",
+					},
+					"pattern": {
+						Type:        "string",
+						Description: "Regex matched against the whole metric name, e.g. 'node_cpu.*' or '.*http_requests.*'",
+					},
+					"hours": {
+						Type:        "integer",
+						Description: "Only metrics with samples in the last N hours (default 1)",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of names to return (default 100, max 1000)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[DiscoveryResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListMetricNamesInput) (*mcp.CallToolResult, error) {
+			if input.DatasourceId <= 0 {
+				return toolset.NewToolResultError("datasource_id is required and must be positive"), nil
+			}
+
+			var matchers []string
+			if input.Pattern != "" {
+				matchers = append(matchers, fmt.Sprintf("{__name__=~%s}", strconv.Quote(input.Pattern)))
+			}
+
+			return discoveryResult(ctx, getClient, input.DatasourceId, "/api/v1/label/__name__/values", matchers, input.Hours, input.Limit), nil
+		}),
+	)
+}
+
+func listLabelNamesTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "list_label_names",
+			Description: "List label names of a Prometheus-compatible datasource, optionally only those of a metric",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Label Names",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"datasource_id"},
+				Properties: map[string]*jsonschema.Schema{
+					"datasource_id": {
+						Type:        "integer",
+						Description: "Datasource ID (see list_datasources)",
+					},
+					"metric": {
+						Type:        "string",
+						Description: "Metric name or series selector, e.g. 'node_load1' or 'up{job=\"node\"}'",
+					},
+					"hours": {
+						Type:        "integer",
+						Description: "Only series with samples in the last N hours (default 1)",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of names to return (default 100, max 1000)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[DiscoveryResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListLabelNamesInput) (*mcp.CallToolResult, error) {
+			if input.DatasourceId <= 0 {
+				return toolset.NewToolResultError("datasource_id is required and must be positive"), nil
+			}
+
+			var matchers []string
+			if input.Metric != "" {
+				matchers = append(matchers, input.Metric)
+			}
+
+			return discoveryResult(ctx, getClient, input.DatasourceId, "/api/v1/labels", matchers, input.Hours, input.Limit), nil
+		}),
+	)
+}
+
+func listLabelValuesTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "list_label_values",
+			Description: "List values of a label in a Prometheus-compatible datasource, optionally under a series selector",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Label Values",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"datasource_id", "label"},
+				Properties: map[string]*jsonschema.Schema{
+					"datasource_id": {
+						Type:        "integer",
+						Description: "Datasource ID (see list_datasources)",
+					},
+					"label": {
+						Type:        "string",
+						Description: "Label name, e.g. 'job' or 'instance'",
+					},
+					"match": {
+						Type:        "string",
+						Description: "Series selector to restrict values, e.g. 'node_load1' or 'up{job=\"node\"}'",
+					},
+					"hours": {
+						Type:        "integer",
+						Description: "Only series with samples in the last N hours (default 1)",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of values to return (default 100, max 1000)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[DiscoveryResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListLabelValuesInput) (*mcp.CallToolResult, error) {
+			if input.DatasourceId <= 0 {
+				return toolset.NewToolResultError("datasource_id is required and must be positive"), nil
+			}
+			if !labelNameRegexp.MatchString(input.Label) {
+				return toolset.NewToolResultError("label is required and must be a valid label name"), nil
+			}
+
+			var matchers []string
+			if input.Match != "" {
+				matchers = append(matchers, input.Match)
+			}
+
+			apiPath := fmt.Sprintf("/api/v1/label/%s/values", input.Label)
+			return discoveryResult(ctx, getClient, input.DatasourceId, apiPath, matchers, input.Hours, input.Limit), nil
+		}),
+	)
+}
+
+// discoveryResult queries a Prometheus label API through the datasource proxy and caps the result
+func discoveryResult(ctx context.Context, getClient client.GetClientFunc, datasourceId int64, apiPath string, matchers []string, hours int64, limit int) *mcp.CallToolResult {
+	if hours < 0 || limit < 0 {
+		return toolset.NewToolResultError("hours and limit must be >= 0")
+	}
+	if limit == 0 {
+		limit = defaultDiscoveryLimit
+	}
+	if limit > maxDiscoveryLimit {
+		limit = maxDiscoveryLimit
+	}
+
+	c := getClient(ctx)
+	if c == nil {
+		return toolset.NewToolResultError("failed to get n9e client from context")
+	}
+
+	stime, etime := resolveTimeRange(hours, 0, 0, 1)
+	params := url.Values{}
+	params.Set("start", strconv.FormatInt(stime, 10))
+	params.Set("end", strconv.FormatInt(etime, 10))
+	for _, m := range matchers {
+		params.Add("match[]", m)
+	}
+
+	resp, err := client.DoGetRaw[types.PromResponse[[]string]](c, ctx, datasourceProxyPath(datasourceId, apiPath), params)
+	if err != nil {
+		return toolset.NewToolResultError(err.Error())
+	}
+	if resp.Status != "success" {
+		return toolset.NewToolResultError(fmt.Sprintf("query failed: %s: %s", resp.ErrorType, resp.Error))
+	}
+
+	result := DiscoveryResult{
+		Values: resp.Data,
+		Total:  len(resp.Data),
+	}
+	if result.Values == nil {
+		result.Values = []string{}
+	}
+	if len(result.Values) > limit {
+		result.Values = result.Values[:limit]
+		result.Truncated = true
+	}
+
+	return toolset.MarshalResult(result)
+}
+
+func queryLogsTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "query_logs",
+			Description: "Search logs in a Loki or Elasticsearch datasource. Returns normalized log lines (newest first) with timestamps and labels. Use this to reproduce log-based alerts.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Query Logs",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"datasource_id", "query"},
+				Properties: map[string]*jsonschema.Schema{
+					"datasource_id": {
+						Type:        "integer",
+						Description: "Loki or Elasticsearch datasource ID (see list_datasources)",
+					},
+					"query": {
+						Type:        "string",
+						Description: "LogQL log query for Loki (e.g. '{app=\"api\"} |= \"error\"'), or Lucene query string for Elasticsearch (e.g. 'level:error AND service:api')",
+					},
+					"index": {
+						Type:        "string",
+						Description: "Elasticsearch index or index pattern, e.g. 'logs-*' (required for Elasticsearch)",
+					},
+					"time_field": {
+						Type:        "string",
+						Description: "Elasticsearch timestamp field (default @timestamp)",
+					},
+					"hours": {
+						Type:        "integer",
+						Description: "Lookback hours from now (mutually exclusive with stime/etime, default 1)",
+					},
+					"stime": {
+						Type:        "integer",
+						Description: "Start time Unix timestamp",
+					},
+					"etime": {
+						Type:        "integer",
+						Description: "End time Unix timestamp (default now)",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of log lines (default 100, max 1000)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[LogQueryResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input QueryLogsInput) (*mcp.CallToolResult, error) {
+			if input.DatasourceId <= 0 {
+				return toolset.NewToolResultError("datasource_id is required and must be positive"), nil
+			}
+			if input.Query == "" {
+				return toolset.NewToolResultError("query is required"), nil
+			}
+			if err := toolset.ValidateTimeRange(input.Hours, input.Stime, input.Etime); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+			if input.Limit < 0 {
+				return toolset.NewToolResultError("limit must be >= 0"), nil
+			}
+
+			limit := input.Limit
+			if limit == 0 {
+				limit = defaultLogLimit
+			}
+			if limit > maxLogLimit {
+				limit = maxLogLimit
+			}
+			stime, etime := resolveTimeRange(input.Hours, input.Stime, input.Etime, 1)
+			if stime >= etime {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: stime (%d) must be less than etime (%d)", stime, etime)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			ds, err := getDatasource(ctx, c, input.DatasourceId)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			var result *LogQueryResult
+			switch ds.PluginType {
+			case "loki":
+				result, err = queryLokiLogs(ctx, c, input.DatasourceId, input.Query, stime, etime, limit)
+			case "elasticsearch":
+				if input.Index == "" || strings.Contains(input.Index, "/") {
+					return toolset.NewToolResultError("index is required for Elasticsearch datasources and must not contain '/'"), nil
+				}
+				timeField := input.TimeField
+				if timeField == "" {
+					timeField = defaultESTimeField
+				}
+				result, err = queryESLogs(ctx, c, input.DatasourceId, input.Index, timeField, input.Query, stime, etime, limit)
+			default:
+				return toolset.NewToolResultError(fmt.Sprintf("datasource %d is of type %s, only loki and elasticsearch are supported", input.DatasourceId, ds.PluginType)), nil
+			}
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+// getDatasource finds a datasource by ID in the datasource brief list
+func getDatasource(ctx context.Context, c *client.Client, datasourceId int64) (*types.Datasource, error) {
+	list, err := client.DoGet[[]types.Datasource](c, ctx, "/api/n9e/datasource/brief", nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].Id == datasourceId {
+			return &list[i], nil
+		}
+	}
+	return nil, fmt.Errorf("datasource %d not found", datasourceId)
+}
+
+// queryLokiLogs searches logs through the Loki query_range API, newest first
+func queryLokiLogs(ctx context.Context, c *client.Client, datasourceId int64, query string, stime, etime int64, limit int) (*LogQueryResult, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(stime*int64(time.Second), 10))
+	params.Set("end", strconv.FormatInt(etime*int64(time.Second), 10))
+	// One extra line tells whether more lines match
+	params.Set("limit", strconv.Itoa(limit+1))
+	params.Set("direction", "backward")
+
+	resp, err := client.DoGetRaw[types.PromResponse[types.LokiQueryData]](c, ctx, datasourceProxyPath(datasourceId, "/loki/api/v1/query_range"), params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("query failed: %s: %s", resp.ErrorType, resp.Error)
+	}
+	if resp.Data.ResultType != "streams" {
+		return nil, fmt.Errorf("query returned %s instead of log streams, use a log query rather than a metric query", resp.Data.ResultType)
+	}
+
+	var streams []types.LokiStream
+	if err := json.Unmarshal(resp.Data.Result, &streams); err != nil {
+		return nil, fmt.Errorf("failed to parse log streams: %w", err)
+	}
+
+	type entry struct {
+		ns   int64
+		line LogLine
+	}
+	var entries []entry
+	for _, stream := range streams {
+		for _, v := range stream.Values {
+			ns, _ := strconv.ParseInt(v[0], 10, 64)
+			entries = append(entries, entry{
+				ns: ns,
+				line: LogLine{
+					Timestamp: time.Unix(0, ns).UTC().Format(time.RFC3339Nano),
+					Line:      truncateLogLine(v[1]),
+					Labels:    stream.Stream,
+				},
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].ns > entries[j].ns })
+
+	result := &LogQueryResult{
+		DatasourceType: "loki",
+		Lines:          make([]LogLine, 0, min(len(entries), limit)),
+		Truncated:      len(entries) > limit,
+	}
+	for i, e := range entries {
+		if i >= limit {
+			break
+		}
+		result.Lines = append(result.Lines, e.line)
+	}
+
+	return result, nil
+}
+
+// queryESLogs searches logs through the Elasticsearch _search API, newest first
+func queryESLogs(ctx context.Context, c *client.Client, datasourceId int64, index, timeField, query string, stime, etime int64, limit int) (*LogQueryResult, error) {
+	body := map[string]any{
+		"size":             limit,
+		"track_total_hits": true,
+		"sort":             []any{map[string]any{timeField: map[string]any{"order": "desc"}}},
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": []any{
+					map[string]any{"range": map[string]any{timeField: map[string]any{
+						"gte":    stime * 1000,
+						"lte":    etime * 1000,
+						"format": "epoch_millis",
+					}}},
+					map[string]any{"query_string": map[string]any{"query": query}},
+				},
+			},
+		},
+	}
+
+	path := datasourceProxyPath(datasourceId, "/"+index+"/_search")
+	resp, err := client.DoPostRaw[types.ESSearchResponse](c, ctx, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("query failed: %v", resp.Error)
+	}
+
+	result := &LogQueryResult{
+		DatasourceType: "elasticsearch",
+		Lines:          make([]LogLine, 0, len(resp.Hits.Hits)),
+		Total:          esTotalHits(resp.Hits.Total),
+	}
+	result.Truncated = result.Total > int64(len(resp.Hits.Hits))
+
+	for _, hit := range resp.Hits.Hits {
+		result.Lines = append(result.Lines, esLogLine(hit, timeField))
+	}
+
+	return result, nil
+}
+
+// esLogLine normalizes an Elasticsearch hit: message field as the line, other scalar fields as labels
+func esLogLine(hit types.ESHit, timeField string) LogLine {
+	line := LogLine{
+		Timestamp: esTimestamp(hit.Source[timeField]),
+		Labels:    map[string]string{"_index": hit.Index},
+	}
+
+	if msg, ok := hit.Source[defaultESMessageField].(string); ok {
+		line.Line = truncateLogLine(msg)
+	} else {
+		data, _ := json.Marshal(hit.Source)
+		line.Line = truncateLogLine(string(data))
+	}
+
+	for k, v := range hit.Source {
+		if k == timeField || k == defaultESMessageField {
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			line.Labels[k] = v
+		case float64:
+			line.Labels[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			line.Labels[k] = strconv.FormatBool(v)
+		}
+	}
+
+	return line
+}
+
+// esTimestamp normalizes an Elasticsearch timestamp (date string or epoch millis) to RFC3339
+func esTimestamp(v any) string {
+	switch v := v.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t.UTC().Format(time.RFC3339Nano)
+		}
+		return v
+	case float64:
+		return time.UnixMilli(int64(v)).UTC().Format(time.RFC3339Nano)
+	}
+	return ""
+}
+
+// esTotalHits reads hits.total, which is a number (ES 6) or {"value": n} (ES 7+)
+func esTotalHits(v any) int64 {
+	switch v := v.(type) {
+	case float64:
+		return int64(v)
+	case map[string]any:
+		if n, ok := v["value"].(float64); ok {
+			return int64(n)
+		}
+	}
+	return 0
+}
+
+// truncateLogLine truncates overly long log lines to keep responses small
+func truncateLogLine(line string) string {
+	if len(line) > maxLogLineLength {
+		return line[:maxLogLineLength] + "...(truncated)"
+	}
+	return line
+}
+
+// resolveTimeRange resolves hours/stime/etime into an absolute range, defaulting to the last defaultHours hours
+func resolveTimeRange(hours, stime, etime, defaultHours int64) (int64, int64) {
+	now := time.Now().Unix()
+	if etime == 0 {
+		etime = now
+	}
+	if hours > 0 {
+		return now - hours*3600, now
+	}
+	if stime == 0 {
+		stime = etime - defaultHours*3600
+	}
+	return stime, etime
+}
+
+// compactQueryResult converts a Prometheus query response into compact series
+func compactQueryResult(resp types.PromResponse[types.PromQueryData], maxSeries int) (*QueryResult, error) {
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("query failed: %s: %s", resp.ErrorType, resp.Error)
+	}
+	if maxSeries == 0 {
+		maxSeries = defaultQueryMaxSeries
+	}
+
+	result := &QueryResult{
+		ResultType: resp.Data.ResultType,
+		Series:     []QuerySeries{},
+		Warnings:   resp.Warnings,
+	}
+
+	switch resp.Data.ResultType {
+	case "vector":
+		var samples []types.PromSample
+		if err := json.Unmarshal(resp.Data.Result, &samples); err != nil {
+			return nil, fmt.Errorf("failed to parse vector result: %w", err)
+		}
+		result.TotalSeries = len(samples)
+		for i, sample := range samples {
+			if i >= maxSeries {
+				result.Truncated = true
+				break
+			}
+			ts, v := parsePromPoint(sample.Value)
+			result.Series = append(result.Series, QuerySeries{Labels: sample.Metric, Value: v, Timestamp: ts})
+		}
+
+	case "matrix":
+		var series []types.PromSeries
+		if err := json.Unmarshal(resp.Data.Result, &series); err != nil {
+			return nil, fmt.Errorf("failed to parse matrix result: %w", err)
+		}
+		result.TotalSeries = len(series)
+		for i, s := range series {
+			if i >= maxSeries {
+				result.Truncated = true
+				break
+			}
+			result.Series = append(result.Series, compactRangeSeries(s))
+		}
+
+	case "scalar", "string":
+		var point [2]any
+		if err := json.Unmarshal(resp.Data.Result, &point); err != nil {
+			return nil, fmt.Errorf("failed to parse %s result: %w", resp.Data.ResultType, err)
+		}
+		ts, v := parsePromPoint(point)
+		result.TotalSeries = 1
+		result.Series = append(result.Series, QuerySeries{Labels: map[string]string{}, Value: v, Timestamp: ts})
+
+	default:
+		return nil, fmt.Errorf("unsupported result type: %s", resp.Data.ResultType)
+	}
+
+	return result, nil
+}
+
+// compactRangeSeries converts a range series into compact points with min/max/last
+func compactRangeSeries(s types.PromSeries) QuerySeries {
+	qs := QuerySeries{
+		Labels: s.Metric,
+		Values: make([][2]any, 0, len(s.Values)),
+	}
+	for _, p := range s.Values {
+		ts, v := parsePromPoint(p)
+		qs.Values = append(qs.Values, [2]any{ts, v})
+		qs.Last = v
+
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		if qs.Min == nil || f < *qs.Min {
+			qs.Min = &f
+		}
+		if qs.Max == nil || f > *qs.Max {
+			qs.Max = &f
+		}
+	}
+	return qs
+}
+
+// parsePromPoint parses a Prometheus [timestamp, "value"] pair
+func parsePromPoint(p [2]any) (int64, string) {
+	var ts int64
+	if f, ok := p[0].(float64); ok {
+		ts = int64(f)
+	}
+	v, _ := p[1].(string)
+	return ts, v
+}
@@ -2,9 +2,14 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/n9e/n9e-mcp-server/pkg/client"
 	"github.com/n9e/n9e-mcp-server/pkg/toolset"
@@ -14,6 +19,13 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+const (
+	pipelineExecutionsPageSize   = 1000
+	defaultExecutionSummaryHours = 24
+	maxExecutionSummaryHours     = 24 * 30
+	maxExecutionSummaryScan      = 20000
+)
+
 // ListEventPipelinesInput represents event pipelines list query parameters
 type ListEventPipelinesInput struct{}
 
@@ -46,6 +58,116 @@ type GetEventPipelineExecutionInput struct {
 	ExecId string `json:"exec_id"`
 }
 
+// SummarizeEventPipelineExecutionsInput represents execution summary parameters
+type SummarizeEventPipelineExecutionsInput struct {
+	PipelineId int64 `json:"pipeline_id,omitempty"`
+	Hours      int   `json:"hours,omitempty"`
+}
+
+// EventPipelineExecutionDetail is an execution record with node results and inputs decoded
+type EventPipelineExecutionDetail struct {
+	types.EventPipelineExecution
+	NodeResults    []types.NodeResult `json:"node_results"`
+	InputsSnapshot map[string]any     `json:"inputs_snapshot,omitempty"`
+	DecodeError    string             `json:"decode_error,omitempty"`
+	RawNodeResults string             `json:"raw_node_results,omitempty"`
+}
+
+// PipelineExecutionStats represents execution statistics of a single pipeline
+type PipelineExecutionStats struct {
+	PipelineId        int64   `json:"pipeline_id"`
+	PipelineName      string  `json:"pipeline_name"`
+	Total             int     `json:"total"`
+	Succeeded         int     `json:"succeeded"`
+	Failed            int     `json:"failed"`
+	Running           int     `json:"running"`
+	FailureRate       float64 `json:"failure_rate"`
+	TopErrorNode      string  `json:"top_error_node,omitempty"`
+	TopErrorNodeCount int     `json:"top_error_node_count,omitempty"`
+	LastError         string  `json:"last_error,omitempty"`
+	LastFailedAt      int64   `json:"last_failed_at,omitempty"`
+}
+
+// EventPipelineExecutionSummary represents execution statistics over a time window
+type EventPipelineExecutionSummary struct {
+	Since     int64                    `json:"since"`
+	Hours     int                      `json:"hours"`
+	Scanned   int                      `json:"scanned"`
+	Truncated bool                     `json:"truncated"`
+	Pipelines []PipelineExecutionStats `json:"pipelines"`
+}
+
+// CreateEventPipelineInput represents create event pipeline parameters
+type CreateEventPipelineInput struct {
+	Pipeline types.EventPipeline `json:"pipeline"`
+}
+
+// UpdateEventPipelineInput represents patch event pipeline parameters
+type UpdateEventPipelineInput struct {
+	PipelineId int64          `json:"id"`
+	Fields     map[string]any `json:"fields"`
+}
+
+// SetEventPipelineDisabledInput represents enable/disable event pipeline parameters
+type SetEventPipelineDisabledInput struct {
+	PipelineId int64 `json:"id"`
+	Disabled   bool  `json:"disabled"`
+}
+
+// TriggerEventPipelineInput represents manual event pipeline trigger parameters
+type TriggerEventPipelineInput struct {
+	PipelineId int64             `json:"id"`
+	EventId    int64             `json:"event_id"`
+	Inputs     map[string]string `json:"inputs,omitempty"`
+}
+
+// EventPipelineStateResult represents the result of enabling/disabling an event pipeline
+type EventPipelineStateResult struct {
+	Id       int64  `json:"id"`
+	Disabled bool   `json:"disabled"`
+	Message  string `json:"message"`
+}
+
+// EventPipelineTriggerResult represents the result of a manual event pipeline trigger
+type EventPipelineTriggerResult struct {
+	Id          int64  `json:"id"`
+	EventId     int64  `json:"event_id"`
+	ExecutionId string `json:"execution_id,omitempty"`
+	Message     string `json:"message"`
+}
+
+// TestEventPipelineInput represents event pipeline dry-run parameters
+type TestEventPipelineInput struct {
+	PipelineId       int64                `json:"id,omitempty"`
+	Pipeline         *types.EventPipeline `json:"pipeline,omitempty"`
+	EventId          int64                `json:"event_id"`
+	Inputs           map[string]string    `json:"inputs,omitempty"`
+	AllowSideEffects bool                 `json:"allow_side_effects,omitempty"`
+}
+
+// EventPipelineTestResult represents the outcome of an event pipeline dry-run
+type EventPipelineTestResult struct {
+	PipelineId   int64               `json:"pipeline_id,omitempty"`
+	PipelineName string              `json:"pipeline_name"`
+	EventId      int64               `json:"event_id"`
+	EventBefore  types.AlertHisEvent `json:"event_before"`
+	EventAfter   any                 `json:"event_after"`
+	EventDropped bool                `json:"event_dropped"`
+	Message      string              `json:"message,omitempty"`
+	Nodes        []types.NodeResult  `json:"nodes,omitempty"`
+}
+
+// eventPipelineImmutableFields are the fields that update_event_pipeline cannot change
+var eventPipelineImmutableFields = map[string]bool{
+	"id": true, "create_at": true, "create_by": true, "update_at": true, "update_by": true,
+}
+
+// sideEffectProcessorTypes are processor and node types that call external services or send notifications
+// when run, test_event_pipeline refuses them unless allow_side_effects is set
+var sideEffectProcessorTypes = map[string]bool{
+	"callback": true, "event_update": true, "ai_summary": true, "script": true, "notify": true,
+}
+
 // RegisterEventPipelinesToolset registers event pipelines toolset
 func RegisterEventPipelinesToolset(group *toolset.ToolsetGroup, getClient client.GetClientFunc) {
 	ts := toolset.NewToolset("event_pipelines", "Event pipeline/workflow management tools for event processing")
@@ -56,6 +178,15 @@ func RegisterEventPipelinesToolset(group *toolset.ToolsetGroup, getClient client
 		listEventPipelineExecutionsTool(getClient),
 		listAllEventPipelineExecutionsTool(getClient),
 		getEventPipelineExecutionTool(getClient),
+		summarizeEventPipelineExecutionsTool(getClient),
+	)
+
+	ts.AddWriteTools(
+		createEventPipelineTool(getClient),
+		updateEventPipelineTool(getClient),
+		setEventPipelineDisabledTool(getClient),
+		testEventPipelineTool(getClient),
+		triggerEventPipelineTool(getClient),
 	)
 
 	group.AddToolset(ts)
@@ -74,6 +205,7 @@ func listEventPipelinesTool(getClient client.GetClientFunc) toolset.ServerTool {
 				Type:       "object",
 				Properties: map[string]*jsonschema.Schema{},
 			},
+			OutputSchema: toolset.OutputSchema[[]types.EventPipeline](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListEventPipelinesInput) (*mcp.CallToolResult, error) {
 			c := getClient(ctx)
@@ -110,6 +242,7 @@ func getEventPipelineTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.EventPipeline](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetEventPipelineInput) (*mcp.CallToolResult, error) {
 			if input.PipelineId <= 0 {
@@ -167,6 +300,7 @@ func listEventPipelineExecutionsTool(getClient client.GetClientFunc) toolset.Ser
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.PageResp[types.EventPipelineExecution]](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListEventPipelineExecutionsInput) (*mcp.CallToolResult, error) {
 			if input.PipelineId <= 0 {
@@ -241,6 +375,7 @@ func listAllEventPipelineExecutionsTool(getClient client.GetClientFunc) toolset.
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.PageResp[types.EventPipelineExecution]](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListAllEventPipelineExecutionsInput) (*mcp.CallToolResult, error) {
 			c := getClient(ctx)
@@ -282,7 +417,7 @@ func getEventPipelineExecutionTool(getClient client.GetClientFunc) toolset.Serve
 	return toolset.NewServerTool(
 		mcp.Tool{
 			Name:        "get_event_pipeline_execution",
-			Description: "Get details of a specific pipeline execution by execution ID",
+			Description: "Get details of a specific pipeline execution by execution ID, with the status, duration, output and error of each node",
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "Get Pipeline Execution",
 				ReadOnlyHint: true,
@@ -297,6 +432,7 @@ func getEventPipelineExecutionTool(getClient client.GetClientFunc) toolset.Serve
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[EventPipelineExecutionDetail](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetEventPipelineExecutionInput) (*mcp.CallToolResult, error) {
 			if input.ExecId == "" {
@@ -314,7 +450,724 @@ func getEventPipelineExecutionTool(getClient client.GetClientFunc) toolset.Serve
 				return toolset.NewToolResultError(err.Error()), nil
 			}
 
-			return toolset.MarshalResult(result), nil
+			return toolset.MarshalResult(decodeExecution(result)), nil
+		}),
+	)
+}
+
+// eventPipelineSchema describes the event pipeline fields
+func eventPipelineSchema() *jsonschema.Schema {
+	tagFilters := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{
+			Type:        "array",
+			Description: description,
+			Items: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"key":   {Type: "string"},
+					"func":  {Type: "string", Description: "Operator: ==, !=, in, not in, =~, !~"},
+					"value": {Type: "string"},
+				},
+			},
+		}
+	}
+
+	return &jsonschema.Schema{
+		Type:        "object",
+		Description: "Event pipeline definition. Use get_event_pipeline on an existing pipeline as a reference for processor and node configs.",
+		Properties: map[string]*jsonschema.Schema{
+			"name":              {Type: "string", Description: "Pipeline name"},
+			"description":       {Type: "string", Description: "Pipeline description"},
+			"typ":               {Type: "string", Description: "Pipeline type"},
+			"use_case":          {Type: "string", Description: "Use case"},
+			"trigger_mode":      {Type: "string", Description: "Trigger mode (event/api/cron)"},
+			"disabled":          {Type: "boolean", Description: "Whether the pipeline is disabled"},
+			"team_ids":          {Type: "array", Description: "User group IDs allowed to manage this pipeline", Items: &jsonschema.Schema{Type: "integer"}},
+			"filter_enable":     {Type: "boolean", Description: "Only process events matching label_filters and attribute_filters"},
+			"label_filters":     tagFilters("Event label filters"),
+			"attribute_filters": tagFilters("Event attribute filters"),
+			"processors": {
+				Type:        "array",
+				Description: "Processors applied in order (e.g. relabel, callback, event_update, event_drop, ai_summary)",
+				Items: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"typ":    {Type: "string", Description: "Processor type"},
+						"config": {Type: "object", Description: "Processor configuration"},
+					},
+				},
+			},
+			"nodes": {
+				Type:        "array",
+				Description: "Workflow nodes (workflow pipelines)",
+				Items: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"id":     {Type: "string", Description: "Node ID, unique in the pipeline"},
+						"type":   {Type: "string", Description: "Node type"},
+						"name":   {Type: "string", Description: "Node name"},
+						"config": {Type: "object", Description: "Node configuration"},
+					},
+				},
+			},
+			"connections": {Type: "object", Description: "Workflow connections between nodes"},
+			"inputs": {
+				Type:        "array",
+				Description: "Input variables, can be set when triggering the pipeline in api mode",
+				Items: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"name":        {Type: "string"},
+						"type":        {Type: "string"},
+						"default":     {Type: "string"},
+						"description": {Type: "string"},
+						"required":    {Type: "boolean"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// validateEventPipeline validates name, filters, processors, nodes and inputs of a pipeline
+func validateEventPipeline(p types.EventPipeline) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if _, err := newTagMatcher(p.LabelFilters); err != nil {
+		return fmt.Errorf("label_filters: %w", err)
+	}
+	if _, err := newTagMatcher(p.AttrFilters); err != nil {
+		return fmt.Errorf("attribute_filters: %w", err)
+	}
+	for i, pc := range p.ProcessorConfigs {
+		if pc.Typ == "" {
+			return fmt.Errorf("processors[%d]: typ is required", i)
+		}
+	}
+	nodeIds := make(map[string]bool, len(p.Nodes))
+	for i, n := range p.Nodes {
+		if n.Id == "" || n.Type == "" {
+			return fmt.Errorf("nodes[%d]: id and type are required", i)
+		}
+		if nodeIds[n.Id] {
+			return fmt.Errorf("nodes[%d]: duplicate node id %s", i, n.Id)
+		}
+		nodeIds[n.Id] = true
+	}
+	inputNames := make(map[string]bool, len(p.Inputs))
+	for i, in := range p.Inputs {
+		if in.Name == "" {
+			return fmt.Errorf("inputs[%d]: name is required", i)
+		}
+		if inputNames[in.Name] {
+			return fmt.Errorf("inputs[%d]: duplicate input %s", i, in.Name)
+		}
+		inputNames[in.Name] = true
+	}
+	return nil
+}
+
+func createEventPipelineTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "create_event_pipeline",
+			Description: "Create a new event pipeline/workflow",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Create Event Pipeline",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"pipeline"},
+				Properties: map[string]*jsonschema.Schema{
+					"pipeline": eventPipelineSchema(),
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input CreateEventPipelineInput) (*mcp.CallToolResult, error) {
+			if err := validateEventPipeline(input.Pipeline); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			pipeline := input.Pipeline
+			pipeline.Id = 0
+			if pipeline.ProcessorConfigs == nil {
+				pipeline.ProcessorConfigs = []types.ProcessorConfig{}
+			}
+
+			result, err := client.DoPost[any](c, ctx, "/api/n9e/event-pipeline", pipeline)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			// Depending on the Nightingale version, the created ID may or may not be returned
+			out := types.ActionResult{Message: "Event pipeline created successfully"}
+			if id, ok := result.(float64); ok {
+				out.Id = int64(id)
+			}
+			return toolset.MarshalResult(out), nil
+		}),
+	)
+}
+
+func updateEventPipelineTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "update_event_pipeline",
+			Description: "Update an event pipeline by changing only the given fields. Other fields keep their current values, given lists (e.g. processors, nodes, inputs) replace the current ones.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Update Event Pipeline",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id", "fields"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Event pipeline ID",
+					},
+					"fields": func() *jsonschema.Schema {
+						s := eventPipelineSchema()
+						s.Description = "Fields to change, same names as in get_event_pipeline output (id and audit fields cannot be changed)"
+						return s
+					}(),
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UpdateEventPipelineInput) (*mcp.CallToolResult, error) {
+			if input.PipelineId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+			if len(input.Fields) == 0 {
+				return toolset.NewToolResultError("fields is required"), nil
+			}
+			for k := range input.Fields {
+				if eventPipelineImmutableFields[k] {
+					return toolset.NewToolResultError(fmt.Sprintf("field %s cannot be changed", k)), nil
+				}
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			if err := patchEventPipeline(ctx, c, input.PipelineId, input.Fields); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      input.PipelineId,
+				Message: "Event pipeline updated successfully",
+			}), nil
 		}),
 	)
 }
+
+func setEventPipelineDisabledTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "set_event_pipeline_disabled",
+			Description: "Enable or disable an event pipeline",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Enable/Disable Event Pipeline",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id", "disabled"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Event pipeline ID",
+					},
+					"disabled": {
+						Type:        "boolean",
+						Description: "true to disable, false to enable",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[EventPipelineStateResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input SetEventPipelineDisabledInput) (*mcp.CallToolResult, error) {
+			if input.PipelineId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			if err := patchEventPipeline(ctx, c, input.PipelineId, map[string]any{"disabled": input.Disabled}); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			message := "Event pipeline enabled successfully"
+			if input.Disabled {
+				message = "Event pipeline disabled successfully"
+			}
+			return toolset.MarshalResult(EventPipelineStateResult{
+				Id:       input.PipelineId,
+				Disabled: input.Disabled,
+				Message:  message,
+			}), nil
+		}),
+	)
+}
+
+// patchEventPipeline fetches the full pipeline, overlays fields, validates and writes it back,
+// so that fields not modeled in types.EventPipeline are preserved
+func patchEventPipeline(ctx context.Context, c *client.Client, pipelineId int64, fields map[string]any) error {
+	pipeline, err := client.DoGet[map[string]any](c, ctx, fmt.Sprintf("/api/n9e/event-pipeline/%d", pipelineId), nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range fields {
+		pipeline[k] = v
+	}
+
+	data, err := json.Marshal(pipeline)
+	if err != nil {
+		return err
+	}
+	var typed types.EventPipeline
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	if err := validateEventPipeline(typed); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+
+	_, err = client.DoPut[any](c, ctx, "/api/n9e/event-pipeline", pipeline)
+	return err
+}
+
+func triggerEventPipelineTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "trigger_event_pipeline",
+			Description: "Run an event pipeline in api mode against an alert event, with optional input variable values. Returns the execution ID, use get_event_pipeline_execution to check the result.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Trigger Event Pipeline",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				OpenWorldHint:   toolset.BoolPtr(true),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id", "event_id"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Event pipeline ID",
+					},
+					"event_id": {
+						Type:        "integer",
+						Description: "Alert event ID to run the pipeline against",
+					},
+					"inputs": {
+						Type:                 "object",
+						Description:          "Input variable values by name, overriding the defaults defined in the pipeline inputs",
+						AdditionalProperties: &jsonschema.Schema{Type: "string"},
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[EventPipelineTriggerResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input TriggerEventPipelineInput) (*mcp.CallToolResult, error) {
+			if input.PipelineId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+			if input.EventId <= 0 {
+				return toolset.NewToolResultError("event_id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			// Check input values against the variables defined by the pipeline
+			pipeline, err := client.DoGet[types.EventPipeline](c, ctx, fmt.Sprintf("/api/n9e/event-pipeline/%d", input.PipelineId), nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+			if err := validatePipelineInputs(pipeline.Inputs, input.Inputs); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			body := map[string]any{
+				"event_id":         input.EventId,
+				"inputs_overrides": input.Inputs,
+			}
+			path := fmt.Sprintf("/api/n9e/event-pipeline/%d/trigger", input.PipelineId)
+			result, err := client.DoPost[map[string]any](c, ctx, path, body)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			executionId, _ := result["execution_id"].(string)
+			return toolset.MarshalResult(EventPipelineTriggerResult{
+				Id:          input.PipelineId,
+				EventId:     input.EventId,
+				ExecutionId: executionId,
+				Message:     "Event pipeline triggered successfully",
+			}), nil
+		}),
+	)
+}
+
+// validatePipelineInputs checks that all values are defined inputs and all required inputs have a value
+func validatePipelineInputs(defined []types.InputVariable, values map[string]string) error {
+	names := make(map[string]bool, len(defined))
+	for _, v := range defined {
+		names[v.Name] = true
+		if v.Required && v.Default == "" && values[v.Name] == "" {
+			return fmt.Errorf("input %s is required", v.Name)
+		}
+	}
+	for name := range values {
+		if !names[name] {
+			return fmt.Errorf("unknown input: %s", name)
+		}
+	}
+	return nil
+}
+
+func testEventPipelineTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "test_event_pipeline",
+			Description: "Dry-run an event pipeline (existing by ID, or an inline definition) against a historical alert event. Returns the event before and after processing and the output of each node. Nothing is saved. Pipelines with processors or nodes calling external services or sending notifications (callback, event_update, ai_summary, script, notify) are refused unless allow_side_effects=true.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Test Event Pipeline",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				OpenWorldHint:   toolset.BoolPtr(true),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"event_id"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Existing event pipeline ID (either id or pipeline is required)",
+					},
+					"pipeline": func() *jsonschema.Schema {
+						s := eventPipelineSchema()
+						s.Description = "Inline event pipeline definition to test without saving it (either id or pipeline is required)"
+						return s
+					}(),
+					"event_id": {
+						Type:        "integer",
+						Description: "Historical alert event ID to run the pipeline against",
+					},
+					"inputs": {
+						Type:                 "object",
+						Description:          "Input variable values by name, overriding the defaults defined in the pipeline inputs",
+						AdditionalProperties: &jsonschema.Schema{Type: "string"},
+					},
+					"allow_side_effects": {
+						Type:        "boolean",
+						Description: "Run processors and nodes that call external services or send notifications (default false)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[EventPipelineTestResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input TestEventPipelineInput) (*mcp.CallToolResult, error) {
+			if (input.PipelineId > 0) == (input.Pipeline != nil) {
+				return toolset.NewToolResultError("exactly one of id or pipeline is required"), nil
+			}
+			if input.PipelineId < 0 {
+				return toolset.NewToolResultError("id must be positive"), nil
+			}
+			if input.EventId <= 0 {
+				return toolset.NewToolResultError("event_id is required and must be positive"), nil
+			}
+			if input.Pipeline != nil {
+				if err := validateEventPipeline(*input.Pipeline); err != nil {
+					return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+				}
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			var pipeline types.EventPipeline
+			if input.Pipeline != nil {
+				pipeline = *input.Pipeline
+			} else {
+				p, err := client.DoGet[types.EventPipeline](c, ctx, fmt.Sprintf("/api/n9e/event-pipeline/%d", input.PipelineId), nil)
+				if err != nil {
+					return toolset.NewToolResultError(err.Error()), nil
+				}
+				pipeline = p
+			}
+			if err := validatePipelineInputs(pipeline.Inputs, input.Inputs); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+			if steps := sideEffectSteps(pipeline); len(steps) > 0 && !input.AllowSideEffects {
+				return toolset.NewToolResultError(fmt.Sprintf("pipeline has steps with external side effects (%s), set allow_side_effects=true to run them", strings.Join(steps, ", "))), nil
+			}
+
+			before, err := client.DoGet[types.AlertHisEvent](c, ctx, fmt.Sprintf("/api/n9e/alert-his-event/%d", input.EventId), nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			// tryrun runs the processors in memory, nothing is persisted or notified
+			body := map[string]any{
+				"event_id":         input.EventId,
+				"pipeline_config":  pipeline,
+				"inputs_overrides": input.Inputs,
+			}
+			result, err := client.DoPost[map[string]any](c, ctx, "/api/n9e/event-pipeline-tryrun", body)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			nodes, err := decodeNodeResults(result["node_results"])
+			if err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("failed to parse node results: %v", err)), nil
+			}
+			message, _ := result["result"].(string)
+
+			return toolset.MarshalResult(EventPipelineTestResult{
+				PipelineId:   pipeline.Id,
+				PipelineName: pipeline.Name,
+				EventId:      input.EventId,
+				EventBefore:  before,
+				EventAfter:   result["event"],
+				EventDropped: result["event"] == nil,
+				Message:      message,
+				Nodes:        nodes,
+			}), nil
+		}),
+	)
+}
+
+// sideEffectSteps lists the processors and workflow nodes of a pipeline that have external side effects
+func sideEffectSteps(pipeline types.EventPipeline) []string {
+	var steps []string
+	for i, pc := range pipeline.ProcessorConfigs {
+		if sideEffectProcessorTypes[pc.Typ] {
+			steps = append(steps, fmt.Sprintf("processors[%d] %s", i, pc.Typ))
+		}
+	}
+	for _, node := range pipeline.Nodes {
+		if sideEffectProcessorTypes[node.Type] {
+			steps = append(steps, fmt.Sprintf("node %s %s", node.Id, node.Type))
+		}
+	}
+	return steps
+}
+
+// decodeNodeResults decodes node results, which are returned either as a JSON string or as a list
+func decodeNodeResults(raw any) ([]types.NodeResult, error) {
+	var data []byte
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		data = []byte(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	}
+
+	var nodes []types.NodeResult
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// decodeExecution decodes the JSON encoded node results and inputs of an execution.
+// Values that cannot be decoded are returned raw along with the decode error.
+func decodeExecution(exec types.EventPipelineExecution) EventPipelineExecutionDetail {
+	detail := EventPipelineExecutionDetail{EventPipelineExecution: exec}
+	var errs []string
+
+	nodes, err := decodeNodeResults(exec.NodeResults)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("node_results: %v", err))
+		detail.RawNodeResults = exec.NodeResults
+	}
+	detail.NodeResults = nodes
+
+	if exec.InputsSnapshot != "" {
+		if err := json.Unmarshal([]byte(exec.InputsSnapshot), &detail.InputsSnapshot); err != nil {
+			errs = append(errs, fmt.Sprintf("inputs_snapshot: %v", err))
+		}
+	}
+
+	detail.DecodeError = strings.Join(errs, "; ")
+	return detail
+}
+
+func summarizeEventPipelineExecutionsTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "summarize_event_pipeline_executions",
+			Description: "Summarize event pipeline executions over a time window: total, failed and running counts, failure rate, and the most common failing node of each pipeline. Pipelines with the most failures come first.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Summarize Pipeline Executions",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"pipeline_id": {
+						Type:        "integer",
+						Description: "Only summarize this pipeline",
+					},
+					"hours": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Time window in hours (default %d, max %d)", defaultExecutionSummaryHours, maxExecutionSummaryHours),
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[EventPipelineExecutionSummary](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input SummarizeEventPipelineExecutionsInput) (*mcp.CallToolResult, error) {
+			if input.Hours < 0 || input.Hours > maxExecutionSummaryHours {
+				return toolset.NewToolResultError(fmt.Sprintf("hours must be between 1 and %d", maxExecutionSummaryHours)), nil
+			}
+			if input.Hours == 0 {
+				input.Hours = defaultExecutionSummaryHours
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			since := time.Now().Add(-time.Duration(input.Hours) * time.Hour).Unix()
+			params := url.Values{}
+			if input.PipelineId > 0 {
+				params.Set("pipeline_id", strconv.FormatInt(input.PipelineId, 10))
+			}
+			execs, truncated, err := listExecutionsSince(ctx, c, params, since)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(EventPipelineExecutionSummary{
+				Since:     since,
+				Hours:     input.Hours,
+				Scanned:   len(execs),
+				Truncated: truncated,
+				Pipelines: summarizeExecutions(execs),
+			}), nil
+		}),
+	)
+}
+
+// listExecutionsSince pages through executions until one is older than since.
+// It relies on the API ordering executions by created_at descending (newest first),
+// so the first older record ends the scan.
+// It stops after maxExecutionSummaryScan records and reports whether the list was truncated.
+func listExecutionsSince(ctx context.Context, c *client.Client, params url.Values, since int64) ([]types.EventPipelineExecution, bool, error) {
+	var execs []types.EventPipelineExecution
+	for page := 1; ; page++ {
+		params.Set("limit", strconv.Itoa(pipelineExecutionsPageSize))
+		params.Set("p", strconv.Itoa(page))
+
+		resp, err := client.DoGet[types.PageResp[types.EventPipelineExecution]](c, ctx, "/api/n9e/event-pipeline-executions", params)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, e := range resp.List {
+			if e.CreatedAt < since {
+				return execs, false, nil
+			}
+			execs = append(execs, e)
+		}
+
+		if len(resp.List) < pipelineExecutionsPageSize {
+			return execs, false, nil
+		}
+		if len(execs) >= maxExecutionSummaryScan {
+			return execs, true, nil
+		}
+	}
+}
+
+// summarizeExecutions groups executions by pipeline, sorted by failure count
+func summarizeExecutions(execs []types.EventPipelineExecution) []PipelineExecutionStats {
+	byPipeline := make(map[int64]*PipelineExecutionStats)
+	errorNodes := make(map[int64]map[string]int)
+	var order []int64
+
+	for _, e := range execs {
+		stats, ok := byPipeline[e.PipelineId]
+		if !ok {
+			stats = &PipelineExecutionStats{PipelineId: e.PipelineId, PipelineName: e.PipelineName}
+			byPipeline[e.PipelineId] = stats
+			errorNodes[e.PipelineId] = make(map[string]int)
+			order = append(order, e.PipelineId)
+		}
+
+		stats.Total++
+		switch e.Status {
+		case "success":
+			stats.Succeeded++
+		case "running":
+			stats.Running++
+		case "failed":
+			stats.Failed++
+			if e.ErrorNode != "" {
+				errorNodes[e.PipelineId][e.ErrorNode]++
+			}
+			// Executions are listed newest first
+			if stats.LastFailedAt == 0 {
+				stats.LastFailedAt = e.CreatedAt
+				stats.LastError = e.ErrorMessage
+			}
+		}
+	}
+
+	result := make([]PipelineExecutionStats, 0, len(order))
+	for _, id := range order {
+		stats := byPipeline[id]
+		if finished := stats.Succeeded + stats.Failed; finished > 0 {
+			stats.FailureRate = math.Round(float64(stats.Failed)/float64(finished)*10000) / 10000
+		}
+		for node, count := range errorNodes[id] {
+			if count > stats.TopErrorNodeCount || (count == stats.TopErrorNodeCount && node < stats.TopErrorNode) {
+				stats.TopErrorNode = node
+				stats.TopErrorNodeCount = count
+			}
+		}
+		result = append(result, *stats)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Failed > result[j].Failed
+	})
+	return result
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/n9e/n9e-mcp-server/pkg/client"
+	"github.com/n9e/n9e-mcp-server/pkg/toolset"
+	"github.com/n9e/n9e-mcp-server/pkg/types"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// instanceCheckTimeout is the timeout for checking a single instance
+const instanceCheckTimeout = 5 * time.Second
+
+// ListInstancesInput represents instances list query parameters
+type ListInstancesInput struct {
+	Instance string `json:"instance,omitempty"`
+}
+
+// InstanceStatus represents reachability of a Nightingale instance
+type InstanceStatus struct {
+	Name      string `json:"name"`
+	BaseURL   string `json:"base_url"`
+	Default   bool   `json:"default"`
+	Reachable bool   `json:"reachable"`
+	Username  string `json:"username,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// RegisterInstancesToolset registers Nightingale instances toolset
+func RegisterInstancesToolset(group *toolset.ToolsetGroup, registry *client.Registry) {
+	ts := toolset.NewToolset("instances", "Nightingale instance (cluster) discovery tools")
+
+	ts.AddReadTools(
+		listInstancesTool(registry),
+	)
+
+	group.AddToolset(ts)
+}
+
+func listInstancesTool(registry *client.Registry) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "list_instances",
+			Description: "List configured Nightingale instances (clusters) and check whether each is reachable with the current token. Use the instance name as the 'instance' argument of other tools.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Instances",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"instance": {
+						Type:        "string",
+						Description: "Only check this instance (default all)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[[]InstanceStatus](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListInstancesInput) (*mcp.CallToolResult, error) {
+			names := registry.Names()
+			if input.Instance != "" {
+				if !registry.Has(input.Instance) {
+					return toolset.NewToolResultError("unknown instance: " + input.Instance), nil
+				}
+				names = []string{input.Instance}
+			}
+
+			tokens := client.SessionTokensFromContext(ctx)
+			result := make([]InstanceStatus, len(names))
+
+			var wg sync.WaitGroup
+			for i, name := range names {
+				wg.Add(1)
+				go func(i int, name string) {
+					defer wg.Done()
+					result[i] = checkInstance(ctx, registry, name, tokens.For(name))
+				}(i, name)
+			}
+			wg.Wait()
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+// checkInstance checks reachability of an instance by fetching the token user's profile
+func checkInstance(ctx context.Context, registry *client.Registry, name, sessionToken string) InstanceStatus {
+	status := InstanceStatus{
+		Name:    name,
+		BaseURL: registry.BaseURL(name),
+		Default: name == registry.DefaultName(),
+	}
+
+	c, err := registry.Client(name, sessionToken)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, instanceCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	user, err := client.DoGet[types.User](c, ctx, "/api/n9e/self/profile", nil)
+	status.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	status.Reachable = true
+	status.Username = user.Username
+	return status
+}
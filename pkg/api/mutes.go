@@ -3,6 +3,12 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/n9e/n9e-mcp-server/pkg/client"
 	"github.com/n9e/n9e-mcp-server/pkg/toolset"
@@ -12,6 +18,11 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+const (
+	defaultMutePreviewLimit = 50
+	maxMutePreviewScan      = 2000
+)
+
 // ListMutesInput represents alert mutes list query parameters
 type ListMutesInput struct {
 	GroupId int64 `json:"group_id"`
@@ -60,6 +71,60 @@ type UpdateMuteInput struct {
 	PeriodicMutes []types.PeriodicMute `json:"periodic_mutes,omitempty"`
 }
 
+// DeleteMutesInput represents delete mute rules parameters
+type DeleteMutesInput struct {
+	GroupId int64   `json:"group_id"`
+	MuteIds []int64 `json:"mute_ids"`
+}
+
+// EndMuteNowInput represents end mute rule now parameters
+type EndMuteNowInput struct {
+	GroupId int64 `json:"group_id"`
+	MuteId  int64 `json:"mute_id"`
+}
+
+// EndMuteResult represents the result of ending a mute rule now
+type EndMuteResult struct {
+	Id       int64  `json:"id"`
+	Etime    int64  `json:"etime,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+	Message  string `json:"message"`
+}
+
+// PreviewMuteInput represents preview mute rule parameters, same as CreateMuteInput plus the events limit
+type PreviewMuteInput struct {
+	CreateMuteInput
+	Limit int `json:"limit,omitempty"`
+}
+
+// MutePreviewEvent represents an active alert event matched by a mute preview
+type MutePreviewEvent struct {
+	Id          int64    `json:"id"`
+	RuleId      int64    `json:"rule_id"`
+	RuleName    string   `json:"rule_name"`
+	Severity    int      `json:"severity"`
+	TargetIdent string   `json:"target_ident,omitempty"`
+	Tags        []string `json:"tags"`
+	TriggerTime int64    `json:"trigger_time"`
+}
+
+// MutePreviewRule represents the number of matched events of an alert rule
+type MutePreviewRule struct {
+	RuleId   int64  `json:"rule_id"`
+	RuleName string `json:"rule_name"`
+	Count    int    `json:"count"`
+}
+
+// MutePreviewResult represents mute preview result
+type MutePreviewResult struct {
+	InEffectNow bool               `json:"in_effect_now"`
+	Scanned     int                `json:"scanned"`
+	Truncated   bool               `json:"truncated"`
+	Matched     int                `json:"matched"`
+	Rules       []MutePreviewRule  `json:"rules"`
+	Events      []MutePreviewEvent `json:"events"`
+}
+
 // RegisterMutesToolset registers alert mutes toolset
 func RegisterMutesToolset(group *toolset.ToolsetGroup, getClient client.GetClientFunc) {
 	ts := toolset.NewToolset("mutes", "Alert mute/silence management tools")
@@ -67,16 +132,106 @@ func RegisterMutesToolset(group *toolset.ToolsetGroup, getClient client.GetClien
 	ts.AddReadTools(
 		listMutesTool(getClient),
 		getMuteTool(getClient),
+		previewMuteTool(getClient),
 	)
 
 	ts.AddWriteTools(
 		createMuteTool(getClient),
 		updateMuteTool(getClient),
+		endMuteNowTool(getClient),
+		deleteMutesTool(getClient),
+	)
+
+	ts.AddResourceTemplates(
+		busiGroupMutesResource(getClient),
+	)
+
+	ts.AddPrompts(
+		draftMutePrompt(group, getClient),
 	)
 
 	group.AddToolset(ts)
 }
 
+// muteRuleProperties adds the mute rule fields shared by create, update and preview to props
+func muteRuleProperties(props map[string]*jsonschema.Schema) map[string]*jsonschema.Schema {
+	for k, v := range map[string]*jsonschema.Schema{
+		"note": {
+			Type:        "string",
+			Description: "Note/title for the mute rule",
+		},
+		"cate": {
+			Type:        "string",
+			Description: "Category (e.g., prometheus, host, elasticsearch)",
+		},
+		"prod": {
+			Type:        "string",
+			Description: "Product type (e.g., metric, host, loki)",
+		},
+		"datasource_ids": {
+			Type:        "array",
+			Description: "Datasource IDs to match (empty means all)",
+			Items:       &jsonschema.Schema{Type: "integer"},
+		},
+		"cluster": {
+			Type:        "string",
+			Description: "Cluster name filter",
+		},
+		"tags": {
+			Type:        "array",
+			Description: "Tag filters. Each filter has key, func (==, !=, in, not in, =~, !~), and value",
+			Items: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"key":   {Type: "string", Description: "Tag key"},
+					"func":  {Type: "string", Description: "Operator: ==, !=, in, not in, =~, !~"},
+					"value": {Type: "string", Description: "Tag value (for 'in'/'not in', space-separated values)"},
+				},
+			},
+		},
+		"cause": {
+			Type:        "string",
+			Description: "Reason/description for the mute",
+		},
+		"btime": {
+			Type:        "integer",
+			Description: "Start time Unix timestamp",
+		},
+		"etime": {
+			Type:        "integer",
+			Description: "End time Unix timestamp",
+		},
+		"severities": {
+			Type:        "array",
+			Description: "Severity levels to match (1=critical, 2=warning, 3=info). Empty means all.",
+			Items:       &jsonschema.Schema{Type: "integer"},
+		},
+		"disabled": {
+			Type:        "integer",
+			Description: "Disabled status (0=enabled, 1=disabled)",
+		},
+		"mute_time_type": {
+			Type:        "integer",
+			Description: "Mute time type (0=time range, 1=periodic)",
+		},
+		"periodic_mutes": {
+			Type:        "array",
+			Description: "Periodic mute rules (when mute_time_type=1)",
+			Items: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"enable_stime":        {Type: "string", Description: "Start time in HH:MM format"},
+					"enable_etime":        {Type: "string", Description: "End time in HH:MM format"},
+					"enable_days_of_week": {Type: "string", Description: "Days of week (0-6, space-separated, 0=Sunday)"},
+				},
+			},
+		},
+	} {
+		props[k] = v
+	}
+	return props
+}
+
 func listMutesTool(getClient client.GetClientFunc) toolset.ServerTool {
 	return toolset.NewServerTool(
 		mcp.Tool{
@@ -96,6 +251,7 @@ func listMutesTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[[]types.AlertMute](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListMutesInput) (*mcp.CallToolResult, error) {
 			if input.GroupId <= 0 {
@@ -141,6 +297,7 @@ func getMuteTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.AlertMute](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetMuteInput) (*mcp.CallToolResult, error) {
 			if input.GroupId <= 0 {
@@ -179,83 +336,14 @@ func createMuteTool(getClient client.GetClientFunc) toolset.ServerTool {
 			InputSchema: &jsonschema.Schema{
 				Type:     "object",
 				Required: []string{"group_id", "cause", "btime", "etime"},
-				Properties: map[string]*jsonschema.Schema{
+				Properties: muteRuleProperties(map[string]*jsonschema.Schema{
 					"group_id": {
 						Type:        "integer",
 						Description: "Business group ID",
 					},
-					"note": {
-						Type:        "string",
-						Description: "Note/title for the mute rule",
-					},
-					"cate": {
-						Type:        "string",
-						Description: "Category (e.g., prometheus, host, elasticsearch)",
-					},
-					"prod": {
-						Type:        "string",
-						Description: "Product type (e.g., metric, host, loki)",
-					},
-					"datasource_ids": {
-						Type:        "array",
-						Description: "Datasource IDs to match (empty means all)",
-						Items:       &jsonschema.Schema{Type: "integer"},
-					},
-					"cluster": {
-						Type:        "string",
-						Description: "Cluster name filter",
-					},
-					"tags": {
-						Type:        "array",
-						Description: "Tag filters. Each filter has key, func (==, !=, in, not in, =~, !~), and value",
-						Items: &jsonschema.Schema{
-							Type: "object",
-							Properties: map[string]*jsonschema.Schema{
-								"key":   {Type: "string", Description: "Tag key"},
-								"func":  {Type: "string", Description: "Operator: ==, !=, in, not in, =~, !~"},
-								"value": {Type: "string", Description: "Tag value (for 'in'/'not in', space-separated values)"},
-							},
-						},
-					},
-					"cause": {
-						Type:        "string",
-						Description: "Reason/description for the mute",
-					},
-					"btime": {
-						Type:        "integer",
-						Description: "Start time Unix timestamp",
-					},
-					"etime": {
-						Type:        "integer",
-						Description: "End time Unix timestamp",
-					},
-					"severities": {
-						Type:        "array",
-						Description: "Severity levels to match (1=critical, 2=warning, 3=info). Empty means all.",
-						Items:       &jsonschema.Schema{Type: "integer"},
-					},
-					"disabled": {
-						Type:        "integer",
-						Description: "Disabled status (0=enabled, 1=disabled)",
-					},
-					"mute_time_type": {
-						Type:        "integer",
-						Description: "Mute time type (0=time range, 1=periodic)",
-					},
-					"periodic_mutes": {
-						Type:        "array",
-						Description: "Periodic mute rules (when mute_time_type=1)",
-						Items: &jsonschema.Schema{
-							Type: "object",
-							Properties: map[string]*jsonschema.Schema{
-								"enable_stime":        {Type: "string", Description: "Start time in HH:MM format"},
-								"enable_etime":        {Type: "string", Description: "End time in HH:MM format"},
-								"enable_days_of_week": {Type: "string", Description: "Days of week (0-6, space-separated, 0=Sunday)"},
-							},
-						},
-					},
-				},
+				}),
 			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input CreateMuteInput) (*mcp.CallToolResult, error) {
 			if input.GroupId <= 0 {
@@ -301,9 +389,9 @@ func createMuteTool(getClient client.GetClientFunc) toolset.ServerTool {
 				return toolset.NewToolResultError(err.Error()), nil
 			}
 
-			return toolset.MarshalResult(map[string]any{
-				"id":      result,
-				"message": "Alert mute created successfully",
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      result,
+				Message: "Alert mute created successfully",
 			}), nil
 		}),
 	)
@@ -322,7 +410,7 @@ func updateMuteTool(getClient client.GetClientFunc) toolset.ServerTool {
 			InputSchema: &jsonschema.Schema{
 				Type:     "object",
 				Required: []string{"group_id", "mute_id", "cause", "btime", "etime"},
-				Properties: map[string]*jsonschema.Schema{
+				Properties: muteRuleProperties(map[string]*jsonschema.Schema{
 					"group_id": {
 						Type:        "integer",
 						Description: "Business group ID",
@@ -331,78 +419,9 @@ func updateMuteTool(getClient client.GetClientFunc) toolset.ServerTool {
 						Type:        "integer",
 						Description: "Alert mute ID to update",
 					},
-					"note": {
-						Type:        "string",
-						Description: "Note/title for the mute rule",
-					},
-					"cate": {
-						Type:        "string",
-						Description: "Category (e.g., prometheus, host, elasticsearch)",
-					},
-					"prod": {
-						Type:        "string",
-						Description: "Product type (e.g., metric, host, loki)",
-					},
-					"datasource_ids": {
-						Type:        "array",
-						Description: "Datasource IDs to match (empty means all)",
-						Items:       &jsonschema.Schema{Type: "integer"},
-					},
-					"cluster": {
-						Type:        "string",
-						Description: "Cluster name filter",
-					},
-					"tags": {
-						Type:        "array",
-						Description: "Tag filters. Each filter has key, func (==, !=, in, not in, =~, !~), and value",
-						Items: &jsonschema.Schema{
-							Type: "object",
-							Properties: map[string]*jsonschema.Schema{
-								"key":   {Type: "string", Description: "Tag key"},
-								"func":  {Type: "string", Description: "Operator: ==, !=, in, not in, =~, !~"},
-								"value": {Type: "string", Description: "Tag value (for 'in'/'not in', space-separated values)"},
-							},
-						},
-					},
-					"cause": {
-						Type:        "string",
-						Description: "Reason/description for the mute",
-					},
-					"btime": {
-						Type:        "integer",
-						Description: "Start time Unix timestamp",
-					},
-					"etime": {
-						Type:        "integer",
-						Description: "End time Unix timestamp",
-					},
-					"severities": {
-						Type:        "array",
-						Description: "Severity levels to match (1=critical, 2=warning, 3=info). Empty means all.",
-						Items:       &jsonschema.Schema{Type: "integer"},
-					},
-					"disabled": {
-						Type:        "integer",
-						Description: "Disabled status (0=enabled, 1=disabled)",
-					},
-					"mute_time_type": {
-						Type:        "integer",
-						Description: "Mute time type (0=time range, 1=periodic)",
-					},
-					"periodic_mutes": {
-						Type:        "array",
-						Description: "Periodic mute rules (when mute_time_type=1)",
-						Items: &jsonschema.Schema{
-							Type: "object",
-							Properties: map[string]*jsonschema.Schema{
-								"enable_stime":        {Type: "string", Description: "Start time in HH:MM format"},
-								"enable_etime":        {Type: "string", Description: "End time in HH:MM format"},
-								"enable_days_of_week": {Type: "string", Description: "Days of week (0-6, space-separated, 0=Sunday)"},
-							},
-						},
-					},
-				},
+				}),
 			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UpdateMuteInput) (*mcp.CallToolResult, error) {
 			if input.GroupId <= 0 {
@@ -451,10 +470,338 @@ func updateMuteTool(getClient client.GetClientFunc) toolset.ServerTool {
 				return toolset.NewToolResultError(err.Error()), nil
 			}
 
-			return toolset.MarshalResult(map[string]any{
-				"id":      input.MuteId,
-				"message": "Alert mute updated successfully",
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      input.MuteId,
+				Message: "Alert mute updated successfully",
 			}), nil
 		}),
 	)
 }
+
+func endMuteNowTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "end_mute_now",
+			Description: "End an alert mute immediately by setting its end time to now, periodic mutes are disabled instead. The mute is kept as a record.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "End Alert Mute Now",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"group_id", "mute_id"},
+				Properties: map[string]*jsonschema.Schema{
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID",
+					},
+					"mute_id": {
+						Type:        "integer",
+						Description: "Alert mute ID to end",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[EndMuteResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input EndMuteNowInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("group_id is required and must be positive"), nil
+			}
+			if input.MuteId <= 0 {
+				return toolset.NewToolResultError("mute_id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			// Fetch the full mute so that fields not modeled in types.AlertMute are preserved
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/alert-mute/%d", input.GroupId, input.MuteId)
+			mute, err := client.DoGet[map[string]any](c, ctx, path, nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			// Periodic mutes ignore btime/etime, they are ended by disabling them
+			if muteTimeType, _ := mute["mute_time_type"].(float64); muteTimeType == 1 {
+				if disabled, _ := mute["disabled"].(float64); disabled == 1 {
+					return toolset.NewToolResultError(fmt.Sprintf("periodic alert mute %d is already disabled", input.MuteId)), nil
+				}
+				mute["disabled"] = 1
+				if _, err := client.DoPut[any](c, ctx, path, mute); err != nil {
+					return toolset.NewToolResultError(err.Error()), nil
+				}
+				return toolset.MarshalResult(EndMuteResult{
+					Id:       input.MuteId,
+					Disabled: true,
+					Message:  "Periodic alert mute disabled successfully",
+				}), nil
+			}
+
+			now := time.Now().Unix()
+			etime, _ := mute["etime"].(float64)
+			if int64(etime) <= now {
+				return toolset.NewToolResultError(fmt.Sprintf("alert mute %d has already ended", input.MuteId)), nil
+			}
+			// A mute scheduled for the future is ended before it starts, btime must stay before etime
+			if btime, _ := mute["btime"].(float64); int64(btime) >= now {
+				mute["btime"] = now - 1
+			}
+			mute["etime"] = now
+
+			if _, err := client.DoPut[any](c, ctx, path, mute); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(EndMuteResult{
+				Id:      input.MuteId,
+				Etime:   now,
+				Message: "Alert mute ended successfully",
+			}), nil
+		}),
+	)
+}
+
+func deleteMutesTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "delete_mutes",
+			Description: "Delete alert mutes/silences by ID. Use end_mute_now instead to stop a mute but keep it as a record.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Delete Alert Mutes",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(true),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"group_id", "mute_ids"},
+				Properties: map[string]*jsonschema.Schema{
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID",
+					},
+					"mute_ids": {
+						Type:        "array",
+						Description: "Alert mute IDs to delete",
+						Items:       &jsonschema.Schema{Type: "integer"},
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input DeleteMutesInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("group_id is required and must be positive"), nil
+			}
+			if len(input.MuteIds) == 0 {
+				return toolset.NewToolResultError("mute_ids is required"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/busi-group/%d/alert-mutes", input.GroupId)
+			if _, err := client.DoDelete[any](c, ctx, path, map[string]any{"ids": input.MuteIds}); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Ids:     input.MuteIds,
+				Message: fmt.Sprintf("%d alert mute(s) deleted successfully", len(input.MuteIds)),
+			}), nil
+		}),
+	)
+}
+
+func previewMuteTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "preview_mute",
+			Description: "Preview which active alerts of a business group a proposed mute would silence, without creating it. Takes the same input as create_mute and returns the matched events and counts per rule.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Preview Alert Mute",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"group_id"},
+				Properties: muteRuleProperties(map[string]*jsonschema.Schema{
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Max matched events to return (default %d, counts always cover all matches)", defaultMutePreviewLimit),
+					},
+				}),
+			},
+			OutputSchema: toolset.OutputSchema[MutePreviewResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input PreviewMuteInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("group_id is required and must be positive"), nil
+			}
+			if input.MuteTimeType == 0 && input.Btime > 0 && input.Etime > 0 && input.Btime >= input.Etime {
+				return toolset.NewToolResultError("btime must be less than etime"), nil
+			}
+			if err := toolset.ValidatePagination(input.Limit, 0); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+			for _, pm := range input.PeriodicMutes {
+				if err := validatePeriodicMute(pm); err != nil {
+					return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+				}
+			}
+			matcher, err := newTagMatcher(input.Tags)
+			if err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			params := url.Values{}
+			params.Set("bgid", strconv.FormatInt(input.GroupId, 10))
+			events, truncated, err := listAllActiveAlerts(ctx, c, params, maxMutePreviewScan)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			limit := input.Limit
+			if limit == 0 {
+				limit = defaultMutePreviewLimit
+			}
+
+			result := MutePreviewResult{
+				InEffectNow: muteInEffect(input.CreateMuteInput, time.Now()),
+				Scanned:     len(events),
+				Truncated:   truncated,
+				Rules:       []MutePreviewRule{},
+				Events:      []MutePreviewEvent{},
+			}
+			ruleIndex := make(map[int64]int)
+			for _, e := range events {
+				if !muteMatchesEvent(input.CreateMuteInput, matcher, e) {
+					continue
+				}
+
+				result.Matched++
+				if i, ok := ruleIndex[e.RuleId]; ok {
+					result.Rules[i].Count++
+				} else {
+					ruleIndex[e.RuleId] = len(result.Rules)
+					result.Rules = append(result.Rules, MutePreviewRule{RuleId: e.RuleId, RuleName: e.RuleName, Count: 1})
+				}
+				if len(result.Events) < limit {
+					result.Events = append(result.Events, MutePreviewEvent{
+						Id:          e.Id,
+						RuleId:      e.RuleId,
+						RuleName:    e.RuleName,
+						Severity:    e.Severity,
+						TargetIdent: e.TargetIdent,
+						Tags:        e.Tags,
+						TriggerTime: e.TriggerTime,
+					})
+				}
+			}
+			sort.SliceStable(result.Rules, func(i, j int) bool {
+				return result.Rules[i].Count > result.Rules[j].Count
+			})
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+// muteMatchesEvent checks whether the mute scope (everything except the time window) matches the event
+func muteMatchesEvent(mute CreateMuteInput, matcher *tagMatcher, e types.AlertCurEvent) bool {
+	if mute.Cate != "" && mute.Cate != "$all" && mute.Cate != e.Cate {
+		return false
+	}
+	if mute.Prod != "" && mute.Prod != e.RuleProd {
+		return false
+	}
+	// An empty list or 0 means all datasources, events without a datasource always match
+	if len(mute.DatasourceIds) > 0 && mute.DatasourceIds[0] != 0 && e.DatasourceId != 0 && !slices.Contains(mute.DatasourceIds, e.DatasourceId) {
+		return false
+	}
+	if len(mute.Severities) > 0 && mute.Severities[0] != 0 && !slices.Contains(mute.Severities, e.Severity) {
+		return false
+	}
+
+	// Mute tag filters may also match the rule name
+	tags := make(map[string]string, len(e.TagsMap)+1)
+	for k, v := range e.TagsMap {
+		tags[k] = v
+	}
+	tags["rulename"] = e.RuleName
+
+	return matcher.Match(tags)
+}
+
+// muteInEffect checks whether the mute is enabled and its time window covers t
+func muteInEffect(mute CreateMuteInput, t time.Time) bool {
+	if mute.Disabled != 0 {
+		return false
+	}
+
+	if mute.MuteTimeType == 0 {
+		ts := t.Unix()
+		return mute.Btime <= ts && ts <= mute.Etime
+	}
+
+	day := strconv.Itoa(int(t.Weekday()))
+	clock := t.Format("15:04")
+	for _, pm := range mute.PeriodicMutes {
+		if !slices.Contains(strings.Fields(pm.EnableDaysOfWeek), day) {
+			continue
+		}
+		if pm.EnableStime <= pm.EnableEtime {
+			if pm.EnableStime <= clock && clock <= pm.EnableEtime {
+				return true
+			}
+		} else if clock >= pm.EnableStime || clock <= pm.EnableEtime {
+			// The window crosses midnight, e.g. 22:00 - 06:00
+			return true
+		}
+	}
+	return false
+}
+
+// validatePeriodicMute validates HH:MM times and days of week of a periodic mute
+func validatePeriodicMute(pm types.PeriodicMute) error {
+	for _, hm := range []string{pm.EnableStime, pm.EnableEtime} {
+		if _, err := time.Parse("15:04", hm); err != nil {
+			return fmt.Errorf("invalid periodic mute time: %q, must be HH:MM", hm)
+		}
+	}
+	for _, d := range strings.Fields(pm.EnableDaysOfWeek) {
+		if n, err := strconv.Atoi(d); err != nil || n < 0 || n > 6 {
+			return fmt.Errorf("invalid periodic mute day of week: %q, must be 0-6", d)
+		}
+	}
+	return nil
+}
+
+func busiGroupMutesResource(getClient client.GetClientFunc) toolset.ServerResourceTemplate {
+	return toolset.NewServerResourceTemplate(
+		mcp.ResourceTemplate{
+			URITemplate: "n9e://busi-group/{id}/mutes",
+			Name:        "busi_group_mutes",
+			Title:       "Business Group Alert Mutes",
+			Description: "Alert mute rules of a business group",
+			MIMEType:    "application/json",
+		},
+		makeResourceHandler(getClient, "n9e://busi-group/", "/mutes", func(ctx context.Context, c *client.Client, id int64) (any, error) {
+			return client.DoGet[[]types.AlertMute](c, ctx, fmt.Sprintf("/api/n9e/busi-group/%d/alert-mutes", id), nil)
+		}),
+	)
+}
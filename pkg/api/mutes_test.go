@@ -0,0 +1,117 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/n9e/n9e-mcp-server/pkg/types"
+)
+
+func TestMuteMatchesEvent(t *testing.T) {
+	event := types.AlertCurEvent{
+		Cate:         "prometheus",
+		RuleProd:     "metric",
+		RuleName:     "cpu high",
+		DatasourceId: 3,
+		Severity:     2,
+		TagsMap:      map[string]string{"env": "prod"},
+	}
+
+	tests := []struct {
+		name string
+		mute CreateMuteInput
+		want bool
+	}{
+		{"empty scope", CreateMuteInput{}, true},
+		{"all cates", CreateMuteInput{Cate: "$all"}, true},
+		{"cate", CreateMuteInput{Cate: "prometheus"}, true},
+		{"cate mismatch", CreateMuteInput{Cate: "host"}, false},
+		{"prod mismatch", CreateMuteInput{Prod: "host"}, false},
+		{"datasource", CreateMuteInput{DatasourceIds: []int64{1, 3}}, true},
+		{"all datasources", CreateMuteInput{DatasourceIds: []int64{0}}, true},
+		{"datasource mismatch", CreateMuteInput{DatasourceIds: []int64{1, 2}}, false},
+		{"severity", CreateMuteInput{Severities: []int{1, 2}}, true},
+		{"all severities", CreateMuteInput{Severities: []int{0}}, true},
+		{"severity mismatch", CreateMuteInput{Severities: []int{1, 3}}, false},
+		{"tag", CreateMuteInput{Tags: []types.TagFilter{{Key: "env", Func: "==", Value: "prod"}}}, true},
+		{"tag mismatch", CreateMuteInput{Tags: []types.TagFilter{{Key: "env", Func: "==", Value: "test"}}}, false},
+		{"rule name tag", CreateMuteInput{Tags: []types.TagFilter{{Key: "rulename", Func: "=~", Value: "^cpu"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newTagMatcher(tt.mute.Tags)
+			if err != nil {
+				t.Fatalf("newTagMatcher() error = %v", err)
+			}
+			if got := muteMatchesEvent(tt.mute, m, event); got != tt.want {
+				t.Errorf("muteMatchesEvent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("event without datasource", func(t *testing.T) {
+		e := event
+		e.DatasourceId = 0
+		m, _ := newTagMatcher(nil)
+		if !muteMatchesEvent(CreateMuteInput{DatasourceIds: []int64{1}}, m, e) {
+			t.Error("muteMatchesEvent() = false, want true")
+		}
+	})
+}
+
+func TestMuteInEffect(t *testing.T) {
+	// 2024-01-01 is a Monday
+	now := time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC)
+	periodic := func(stime, etime, days string) CreateMuteInput {
+		return CreateMuteInput{
+			MuteTimeType:  1,
+			PeriodicMutes: []types.PeriodicMute{{EnableStime: stime, EnableEtime: etime, EnableDaysOfWeek: days}},
+		}
+	}
+
+	tests := []struct {
+		name string
+		mute CreateMuteInput
+		want bool
+	}{
+		{"fixed window", CreateMuteInput{Btime: now.Unix() - 60, Etime: now.Unix() + 60}, true},
+		{"fixed window ended", CreateMuteInput{Btime: now.Unix() - 120, Etime: now.Unix() - 60}, false},
+		{"fixed window not started", CreateMuteInput{Btime: now.Unix() + 60, Etime: now.Unix() + 120}, false},
+		{"disabled", CreateMuteInput{Btime: now.Unix() - 60, Etime: now.Unix() + 60, Disabled: 1}, false},
+		{"periodic", periodic("23:00", "23:59", "1"), true},
+		{"periodic other day", periodic("23:00", "23:59", "0 2 3 4 5 6"), false},
+		{"periodic outside hours", periodic("09:00", "18:00", "1"), false},
+		{"periodic across midnight", periodic("22:00", "06:00", "1"), true},
+		{"periodic across midnight outside", periodic("00:30", "00:10", "1"), true},
+		{"periodic across midnight before start", periodic("23:45", "06:00", "1"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := muteInEffect(tt.mute, now); got != tt.want {
+				t.Errorf("muteInEffect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePeriodicMute(t *testing.T) {
+	tests := []struct {
+		name    string
+		pm      types.PeriodicMute
+		wantErr bool
+	}{
+		{"valid", types.PeriodicMute{EnableStime: "09:00", EnableEtime: "18:00", EnableDaysOfWeek: "1 2 3 4 5"}, false},
+		{"all days", types.PeriodicMute{EnableStime: "00:00", EnableEtime: "23:59", EnableDaysOfWeek: "0 1 2 3 4 5 6"}, false},
+		{"invalid start", types.PeriodicMute{EnableStime: "9am", EnableEtime: "18:00", EnableDaysOfWeek: "1"}, true},
+		{"invalid end", types.PeriodicMute{EnableStime: "09:00", EnableEtime: "24:00", EnableDaysOfWeek: "1"}, true},
+		{"day out of range", types.PeriodicMute{EnableStime: "09:00", EnableEtime: "18:00", EnableDaysOfWeek: "1 7"}, true},
+		{"day not a number", types.PeriodicMute{EnableStime: "09:00", EnableEtime: "18:00", EnableDaysOfWeek: "mon"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePeriodicMute(tt.pm); (err != nil) != tt.wantErr {
+				t.Errorf("validatePeriodicMute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
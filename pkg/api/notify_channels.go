@@ -0,0 +1,306 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/n9e/n9e-mcp-server/pkg/client"
+	"github.com/n9e/n9e-mcp-server/pkg/toolset"
+	"github.com/n9e/n9e-mcp-server/pkg/types"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// GetNotifyChannelInput represents single notification channel query parameters
+type GetNotifyChannelInput struct {
+	ChannelId int64 `json:"id"`
+}
+
+// ListMessageTemplatesInput represents message templates list query parameters
+type ListMessageTemplatesInput struct {
+	ChannelIdent string `json:"notify_channel_ident,omitempty"`
+}
+
+// GetMessageTemplateInput represents single message template query parameters
+type GetMessageTemplateInput struct {
+	TemplateId int64 `json:"id"`
+}
+
+// RenderMessageTemplateInput represents render message template parameters
+type RenderMessageTemplateInput struct {
+	TemplateId int64 `json:"template_id"`
+	EventId    int64 `json:"event_id"`
+}
+
+// RenderedMessageTemplate represents a message template rendered with an alert event, keyed by template field
+type RenderedMessageTemplate struct {
+	TemplateId   int64             `json:"template_id"`
+	TemplateName string            `json:"template_name"`
+	EventId      int64             `json:"event_id"`
+	Rendered     map[string]string `json:"rendered"`
+}
+
+// RegisterNotifyChannelsToolset registers notification channels and message templates toolset
+func RegisterNotifyChannelsToolset(group *toolset.ToolsetGroup, getClient client.GetClientFunc) {
+	ts := toolset.NewToolset("notify_channels", "Notification channel and message template tools")
+
+	ts.AddReadTools(
+		listNotifyChannelsTool(getClient),
+		getNotifyChannelTool(getClient),
+		listMessageTemplatesTool(getClient),
+		getMessageTemplateTool(getClient),
+		renderMessageTemplateTool(getClient),
+	)
+
+	group.AddToolset(ts)
+}
+
+func listNotifyChannelsTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "list_notify_channels",
+			Description: "List notification channels (e.g. email, dingtalk, wecom, webhook) referenced by channel_id in notify rules",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Notification Channels",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{},
+			},
+			OutputSchema: toolset.OutputSchema[[]types.NotifyChannel](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, error) {
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			result, err := client.DoGet[[]types.NotifyChannel](c, ctx, "/api/n9e/notify-channel-configs", nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+func getNotifyChannelTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "get_notify_channel",
+			Description: "Get details of a specific notification channel by ID",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Notification Channel",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Notification channel ID",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.NotifyChannel](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetNotifyChannelInput) (*mcp.CallToolResult, error) {
+			if input.ChannelId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/notify-channel-config/%d", input.ChannelId)
+			result, err := client.DoGet[types.NotifyChannel](c, ctx, path, nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+func listMessageTemplatesTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "list_message_templates",
+			Description: "List notification message templates referenced by template_id in notify rules",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Message Templates",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"notify_channel_ident": {
+						Type:        "string",
+						Description: "Only list templates of this channel ident (e.g. email, dingtalk)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[[]types.MessageTemplate](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListMessageTemplatesInput) (*mcp.CallToolResult, error) {
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			templates, err := client.DoGet[[]types.MessageTemplate](c, ctx, "/api/n9e/message-templates", nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			if input.ChannelIdent == "" {
+				return toolset.MarshalResult(templates), nil
+			}
+			result := make([]types.MessageTemplate, 0, len(templates))
+			for _, t := range templates {
+				if t.NotifyChannelIdent == input.ChannelIdent {
+					result = append(result, t)
+				}
+			}
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+func getMessageTemplateTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "get_message_template",
+			Description: "Get details of a specific message template by ID, including its content",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Message Template",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Message template ID",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.MessageTemplate](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetMessageTemplateInput) (*mcp.CallToolResult, error) {
+			if input.TemplateId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/message-template/%d", input.TemplateId)
+			result, err := client.DoGet[types.MessageTemplate](c, ctx, path, nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+func renderMessageTemplateTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "render_message_template",
+			Description: "Render a message template against a historical alert event to preview the notification text. Nothing is sent.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Render Message Template",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"template_id", "event_id"},
+				Properties: map[string]*jsonschema.Schema{
+					"template_id": {
+						Type:        "integer",
+						Description: "Message template ID",
+					},
+					"event_id": {
+						Type:        "integer",
+						Description: "Historical alert event ID",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[RenderedMessageTemplate](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input RenderMessageTemplateInput) (*mcp.CallToolResult, error) {
+			if input.TemplateId <= 0 {
+				return toolset.NewToolResultError("template_id is required and must be positive"), nil
+			}
+			if input.EventId <= 0 {
+				return toolset.NewToolResultError("event_id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			tpl, err := client.DoGet[types.MessageTemplate](c, ctx, fmt.Sprintf("/api/n9e/message-template/%d", input.TemplateId), nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			body := map[string]any{
+				"event_ids": []int64{input.EventId},
+				"tpl":       map[string]any{"content": tpl.Content},
+			}
+			rendered, err := client.DoPost[map[string]string](c, ctx, "/api/n9e/events-message", body)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(RenderedMessageTemplate{
+				TemplateId:   input.TemplateId,
+				TemplateName: tpl.Name,
+				EventId:      input.EventId,
+				Rendered:     rendered,
+			}), nil
+		}),
+	)
+}
+
+// inlineNotifyNames fills channel and template names of notify configs.
+// Lookups are best effort, names are left empty if they cannot be resolved.
+func inlineNotifyNames(ctx context.Context, c *client.Client, rules []types.NotifyRule) {
+	channels := make(map[int64]string)
+	if list, err := client.DoGet[[]types.NotifyChannel](c, ctx, "/api/n9e/notify-channel-configs", nil); err == nil {
+		for _, ch := range list {
+			channels[ch.Id] = ch.Name
+		}
+	}
+
+	templates := make(map[int64]string)
+	if list, err := client.DoGet[[]types.MessageTemplate](c, ctx, "/api/n9e/message-templates", nil); err == nil {
+		for _, t := range list {
+			templates[t.Id] = t.Name
+		}
+	}
+
+	for i := range rules {
+		for j := range rules[i].NotifyConfigs {
+			nc := &rules[i].NotifyConfigs[j]
+			nc.ChannelName = channels[nc.ChannelID]
+			nc.TemplateName = templates[nc.TemplateID]
+		}
+	}
+}
@@ -3,6 +3,9 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/n9e/n9e-mcp-server/pkg/client"
 	"github.com/n9e/n9e-mcp-server/pkg/toolset"
@@ -13,11 +16,71 @@ import (
 )
 
 // ListNotifyRulesInput represents notification rules list query parameters
-type ListNotifyRulesInput struct{}
+type ListNotifyRulesInput struct {
+	InlineNames bool `json:"inline_names,omitempty"`
+}
 
 // GetNotifyRuleInput represents single notification rule query parameters
 type GetNotifyRuleInput struct {
+	RuleId      int64 `json:"id"`
+	InlineNames bool  `json:"inline_names,omitempty"`
+}
+
+// CreateNotifyRuleInput represents create notification rule parameters
+type CreateNotifyRuleInput struct {
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description,omitempty"`
+	Enable          *bool                  `json:"enable,omitempty"`
+	UserGroupIds    []int64                `json:"user_group_ids"`
+	PipelineConfigs []types.PipelineConfig `json:"pipeline_configs,omitempty"`
+	NotifyConfigs   []types.NotifyConfig   `json:"notify_configs"`
+}
+
+// UpdateNotifyRuleInput represents update notification rule parameters, omitted fields are unchanged
+type UpdateNotifyRuleInput struct {
+	RuleId          int64                  `json:"id"`
+	Name            *string                `json:"name,omitempty"`
+	Description     *string                `json:"description,omitempty"`
+	Enable          *bool                  `json:"enable,omitempty"`
+	UserGroupIds    []int64                `json:"user_group_ids,omitempty"`
+	PipelineConfigs []types.PipelineConfig `json:"pipeline_configs,omitempty"`
+	NotifyConfigs   []types.NotifyConfig   `json:"notify_configs,omitempty"`
+}
+
+// SetNotifyRuleEnabledInput represents enable/disable notification rule parameters
+type SetNotifyRuleEnabledInput struct {
 	RuleId int64 `json:"id"`
+	Enable bool  `json:"enable"`
+}
+
+// TestNotifyRuleInput represents test notification rule parameters
+type TestNotifyRuleInput struct {
+	RuleId      int64   `json:"id"`
+	EventIds    []int64 `json:"event_ids,omitempty"`
+	ConfigIndex *int    `json:"config_index,omitempty"`
+}
+
+// NotifyTestResult represents the test result of a notify config
+type NotifyTestResult struct {
+	ConfigIndex int    `json:"config_index"`
+	ChannelId   int64  `json:"channel_id"`
+	Success     bool   `json:"success"`
+	Result      any    `json:"result,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// NotifyRuleTestReport represents the test results of all tested notify configs of a notification rule
+type NotifyRuleTestReport struct {
+	Id       int64              `json:"id"`
+	EventIds []int64            `json:"event_ids"`
+	Results  []NotifyTestResult `json:"results"`
+}
+
+// NotifyRuleStateResult represents the result of enabling/disabling a notification rule
+type NotifyRuleStateResult struct {
+	Id      int64  `json:"id"`
+	Enable  bool   `json:"enable"`
+	Message string `json:"message"`
 }
 
 // RegisterNotifyRulesToolset registers notification rules toolset
@@ -29,6 +92,13 @@ func RegisterNotifyRulesToolset(group *toolset.ToolsetGroup, getClient client.Ge
 		getNotifyRuleTool(getClient),
 	)
 
+	ts.AddWriteTools(
+		createNotifyRuleTool(getClient),
+		updateNotifyRuleTool(getClient),
+		setNotifyRuleEnabledTool(getClient),
+		testNotifyRuleTool(getClient),
+	)
+
 	group.AddToolset(ts)
 }
 
@@ -42,9 +112,15 @@ func listNotifyRulesTool(getClient client.GetClientFunc) toolset.ServerTool {
 				ReadOnlyHint: true,
 			},
 			InputSchema: &jsonschema.Schema{
-				Type:       "object",
-				Properties: map[string]*jsonschema.Schema{},
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"inline_names": {
+						Type:        "boolean",
+						Description: "Inline channel_name and template_name into notify_configs",
+					},
+				},
 			},
+			OutputSchema: toolset.OutputSchema[[]types.NotifyRule](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListNotifyRulesInput) (*mcp.CallToolResult, error) {
 			c := getClient(ctx)
@@ -56,6 +132,9 @@ func listNotifyRulesTool(getClient client.GetClientFunc) toolset.ServerTool {
 			if err != nil {
 				return toolset.NewToolResultError(err.Error()), nil
 			}
+			if input.InlineNames {
+				inlineNotifyNames(ctx, c, result)
+			}
 
 			return toolset.MarshalResult(result), nil
 		}),
@@ -79,8 +158,13 @@ func getNotifyRuleTool(getClient client.GetClientFunc) toolset.ServerTool {
 						Type:        "integer",
 						Description: "Notification rule ID",
 					},
+					"inline_names": {
+						Type:        "boolean",
+						Description: "Inline channel_name and template_name into notify_configs",
+					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.NotifyRule](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetNotifyRuleInput) (*mcp.CallToolResult, error) {
 			if input.RuleId <= 0 {
@@ -97,8 +181,436 @@ func getNotifyRuleTool(getClient client.GetClientFunc) toolset.ServerTool {
 			if err != nil {
 				return toolset.NewToolResultError(err.Error()), nil
 			}
+			if input.InlineNames {
+				rules := []types.NotifyRule{result}
+				inlineNotifyNames(ctx, c, rules)
+				result = rules[0]
+			}
 
 			return toolset.MarshalResult(result), nil
 		}),
 	)
 }
+
+// notifyConfigsSchema describes the notify configs of a notification rule
+func notifyConfigsSchema() *jsonschema.Schema {
+	tagFilters := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{
+			Type:        "array",
+			Description: description,
+			Items: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"key":   {Type: "string", Description: "Label key"},
+					"func":  {Type: "string", Description: "Operator: ==, !=, in, not in, =~, !~"},
+					"value": {Type: "string", Description: "Value (for 'in'/'not in', space-separated values)"},
+				},
+			},
+		}
+	}
+
+	return &jsonschema.Schema{
+		Type:        "array",
+		Description: "Notify configs, each sends matching events to a channel with a message template. Use get_notify_rule on an existing rule as a reference for params.",
+		Items: &jsonschema.Schema{
+			Type:     "object",
+			Required: []string{"channel_id"},
+			Properties: map[string]*jsonschema.Schema{
+				"channel_id":  {Type: "integer", Description: "Notification channel ID"},
+				"template_id": {Type: "integer", Description: "Message template ID"},
+				"params":      {Type: "object", Description: "Channel parameters, e.g. {\"user_group_ids\": [1]} or {\"user_ids\": [1]}"},
+				"type":        {Type: "string", Description: "Channel request type"},
+				"severities": {
+					Type:        "array",
+					Description: "Severity levels to notify (1=critical, 2=warning, 3=info)",
+					Items:       &jsonschema.Schema{Type: "integer"},
+				},
+				"time_ranges": {
+					Type:        "array",
+					Description: "Time ranges when notifications are sent (empty means always)",
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"start_time": {Type: "string", Description: "Start time in HH:MM format"},
+							"end_time":   {Type: "string", Description: "End time in HH:MM format"},
+							"weekdays":   {Type: "array", Description: "Days of week (0-6, 0=Sunday)", Items: &jsonschema.Schema{Type: "integer"}},
+						},
+					},
+				},
+				"label_keys": tagFilters("Event label filters"),
+				"attributes": tagFilters("Event attribute filters (e.g. group_name, rule_name)"),
+			},
+		},
+	}
+}
+
+// validateNotifyConfigs validates channel, severities, time ranges and label filters of notify configs
+func validateNotifyConfigs(configs []types.NotifyConfig) error {
+	for i, nc := range configs {
+		if nc.ChannelID <= 0 {
+			return fmt.Errorf("notify_configs[%d]: channel_id is required and must be positive", i)
+		}
+		for _, sev := range nc.Severities {
+			if !toolset.ValidSeverities[sev] {
+				return fmt.Errorf("notify_configs[%d]: invalid severity %d, must be 1, 2, or 3", i, sev)
+			}
+		}
+		for _, tr := range nc.TimeRanges {
+			for _, hm := range []string{tr.StartTime, tr.EndTime} {
+				if _, err := time.Parse("15:04", hm); err != nil {
+					return fmt.Errorf("notify_configs[%d]: invalid time %q, must be HH:MM", i, hm)
+				}
+			}
+			for _, d := range tr.Weekdays {
+				if d < 0 || d > 6 {
+					return fmt.Errorf("notify_configs[%d]: invalid weekday %d, must be 0-6", i, d)
+				}
+			}
+		}
+		if _, err := newTagMatcher(nc.LabelKeys); err != nil {
+			return fmt.Errorf("notify_configs[%d]: label_keys: %w", i, err)
+		}
+		if _, err := newTagMatcher(nc.Attributes); err != nil {
+			return fmt.Errorf("notify_configs[%d]: attributes: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// notifyRuleProperties adds the notification rule fields shared by create and update to props
+func notifyRuleProperties(props map[string]*jsonschema.Schema) map[string]*jsonschema.Schema {
+	props["name"] = &jsonschema.Schema{Type: "string", Description: "Rule name"}
+	props["description"] = &jsonschema.Schema{Type: "string", Description: "Rule description"}
+	props["enable"] = &jsonschema.Schema{Type: "boolean", Description: "Whether the rule is enabled"}
+	props["user_group_ids"] = &jsonschema.Schema{
+		Type:        "array",
+		Description: "User group IDs allowed to manage this rule",
+		Items:       &jsonschema.Schema{Type: "integer"},
+	}
+	props["pipeline_configs"] = &jsonschema.Schema{
+		Type:        "array",
+		Description: "Event pipelines applied before notifying",
+		Items: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"pipeline_id": {Type: "integer", Description: "Event pipeline ID"},
+				"enable":      {Type: "boolean", Description: "Whether the pipeline is enabled"},
+			},
+		},
+	}
+	props["notify_configs"] = notifyConfigsSchema()
+	return props
+}
+
+func createNotifyRuleTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "create_notify_rule",
+			Description: "Create a new notification rule",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Create Notification Rule",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Required:   []string{"name", "user_group_ids", "notify_configs"},
+				Properties: notifyRuleProperties(map[string]*jsonschema.Schema{}),
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input CreateNotifyRuleInput) (*mcp.CallToolResult, error) {
+			if strings.TrimSpace(input.Name) == "" {
+				return toolset.NewToolResultError("name is required"), nil
+			}
+			if len(input.UserGroupIds) == 0 {
+				return toolset.NewToolResultError("user_group_ids is required"), nil
+			}
+			if len(input.NotifyConfigs) == 0 {
+				return toolset.NewToolResultError("notify_configs is required"), nil
+			}
+			if err := validateNotifyConfigs(input.NotifyConfigs); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			rule := types.NotifyRule{
+				Name:            strings.TrimSpace(input.Name),
+				Description:     input.Description,
+				Enable:          input.Enable == nil || *input.Enable,
+				UserGroupIds:    input.UserGroupIds,
+				PipelineConfigs: input.PipelineConfigs,
+				NotifyConfigs:   input.NotifyConfigs,
+			}
+			if rule.PipelineConfigs == nil {
+				rule.PipelineConfigs = []types.PipelineConfig{}
+			}
+
+			result, err := client.DoPost[any](c, ctx, "/api/n9e/notify-rules", []types.NotifyRule{rule})
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			// Depending on the Nightingale version, the created IDs may or may not be returned
+			out := types.ActionResult{Message: "Notification rule created successfully"}
+			if ids, ok := result.([]any); ok && len(ids) == 1 {
+				if id, ok := ids[0].(float64); ok {
+					out.Id = int64(id)
+				}
+			}
+			return toolset.MarshalResult(out), nil
+		}),
+	)
+}
+
+func updateNotifyRuleTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "update_notify_rule",
+			Description: "Update a notification rule. Omitted fields are left unchanged, given lists (e.g. notify_configs) replace the current ones.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Update Notification Rule",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id"},
+				Properties: notifyRuleProperties(map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Notification rule ID",
+					},
+				}),
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UpdateNotifyRuleInput) (*mcp.CallToolResult, error) {
+			if input.RuleId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+			if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+				return toolset.NewToolResultError("name cannot be empty"), nil
+			}
+			if err := validateNotifyConfigs(input.NotifyConfigs); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			fields := map[string]any{}
+			if input.Name != nil {
+				fields["name"] = strings.TrimSpace(*input.Name)
+			}
+			if input.Description != nil {
+				fields["description"] = *input.Description
+			}
+			if input.Enable != nil {
+				fields["enable"] = *input.Enable
+			}
+			if input.UserGroupIds != nil {
+				fields["user_group_ids"] = input.UserGroupIds
+			}
+			if input.PipelineConfigs != nil {
+				fields["pipeline_configs"] = input.PipelineConfigs
+			}
+			if input.NotifyConfigs != nil {
+				fields["notify_configs"] = input.NotifyConfigs
+			}
+			if len(fields) == 0 {
+				return toolset.NewToolResultError("at least one field to update is required"), nil
+			}
+
+			if err := patchNotifyRule(ctx, c, input.RuleId, fields); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      input.RuleId,
+				Message: "Notification rule updated successfully",
+			}), nil
+		}),
+	)
+}
+
+func setNotifyRuleEnabledTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "set_notify_rule_enabled",
+			Description: "Enable or disable a notification rule",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Enable/Disable Notification Rule",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id", "enable"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Notification rule ID",
+					},
+					"enable": {
+						Type:        "boolean",
+						Description: "true to enable, false to disable",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[NotifyRuleStateResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input SetNotifyRuleEnabledInput) (*mcp.CallToolResult, error) {
+			if input.RuleId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			if err := patchNotifyRule(ctx, c, input.RuleId, map[string]any{"enable": input.Enable}); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			message := "Notification rule enabled successfully"
+			if !input.Enable {
+				message = "Notification rule disabled successfully"
+			}
+			return toolset.MarshalResult(NotifyRuleStateResult{
+				Id:      input.RuleId,
+				Enable:  input.Enable,
+				Message: message,
+			}), nil
+		}),
+	)
+}
+
+// patchNotifyRule fetches the full rule, overlays fields and writes it back,
+// so that fields not modeled in types.NotifyRule are preserved
+func patchNotifyRule(ctx context.Context, c *client.Client, ruleId int64, fields map[string]any) error {
+	path := fmt.Sprintf("/api/n9e/notify-rule/%d", ruleId)
+	rule, err := client.DoGet[map[string]any](c, ctx, path, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range fields {
+		rule[k] = v
+	}
+	_, err = client.DoPut[any](c, ctx, path, rule)
+	return err
+}
+
+func testNotifyRuleTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "test_notify_rule",
+			Description: "Send historical alert events through the notify configs of a notification rule to check delivery. Without event_ids, the most recent historical alert event is used as a sample. This sends real notifications.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Test Notification Rule",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				OpenWorldHint:   toolset.BoolPtr(true),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "Notification rule ID",
+					},
+					"event_ids": {
+						Type:        "array",
+						Description: "Historical alert event IDs to send (default: the most recent historical event)",
+						Items:       &jsonschema.Schema{Type: "integer"},
+					},
+					"config_index": {
+						Type:        "integer",
+						Description: "Only test the notify config at this index (0-based, default all)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[NotifyRuleTestReport](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input TestNotifyRuleInput) (*mcp.CallToolResult, error) {
+			if input.RuleId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			rule, err := client.DoGet[types.NotifyRule](c, ctx, fmt.Sprintf("/api/n9e/notify-rule/%d", input.RuleId), nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+			if len(rule.NotifyConfigs) == 0 {
+				return toolset.NewToolResultError(fmt.Sprintf("notification rule %d has no notify configs", input.RuleId)), nil
+			}
+
+			indexes := make([]int, 0, len(rule.NotifyConfigs))
+			if input.ConfigIndex != nil {
+				if *input.ConfigIndex < 0 || *input.ConfigIndex >= len(rule.NotifyConfigs) {
+					return toolset.NewToolResultError(fmt.Sprintf("config_index must be between 0 and %d", len(rule.NotifyConfigs)-1)), nil
+				}
+				indexes = append(indexes, *input.ConfigIndex)
+			} else {
+				for i := range rule.NotifyConfigs {
+					indexes = append(indexes, i)
+				}
+			}
+
+			eventIds := input.EventIds
+			if len(eventIds) == 0 {
+				params := url.Values{}
+				params.Set("limit", "1")
+				params.Set("p", "1")
+				events, err := client.DoGet[types.PageResp[types.AlertHisEvent]](c, ctx, "/api/n9e/alert-his-events/list", params)
+				if err != nil {
+					return toolset.NewToolResultError(err.Error()), nil
+				}
+				if len(events.List) == 0 {
+					return toolset.NewToolResultError("no historical alert event found to use as a sample, please specify event_ids"), nil
+				}
+				eventIds = []int64{events.List[0].Id}
+			}
+
+			results := make([]NotifyTestResult, 0, len(indexes))
+			for _, i := range indexes {
+				nc := rule.NotifyConfigs[i]
+				r := NotifyTestResult{ConfigIndex: i, ChannelId: nc.ChannelID}
+
+				body := map[string]any{
+					"event_ids":     eventIds,
+					"notify_config": nc,
+				}
+				resp, err := client.DoPost[any](c, ctx, "/api/n9e/notify-rule/test", body)
+				if err != nil {
+					r.Error = err.Error()
+				} else {
+					r.Success = true
+					r.Result = resp
+				}
+				results = append(results, r)
+			}
+
+			return toolset.MarshalResult(NotifyRuleTestReport{
+				Id:       input.RuleId,
+				EventIds: eventIds,
+				Results:  results,
+			}), nil
+		}),
+	)
+}
@@ -0,0 +1,532 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/n9e/n9e-mcp-server/pkg/client"
+	"github.com/n9e/n9e-mcp-server/pkg/toolset"
+	"github.com/n9e/n9e-mcp-server/pkg/types"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	triageHistoryHours       = 7 * 24
+	triageHistoryLimit       = 5000
+	triageRecentOccurrences  = 10
+	draftMuteAlertsLimit     = 200
+	weeklyReviewHistoryLimit = 5000
+	weeklyReviewTopRules     = 20
+)
+
+// AlertOccurrence is a compact view of a historical alert event
+type AlertOccurrence struct {
+	Id          int64 `json:"id"`
+	TriggerTime int64 `json:"trigger_time"`
+	IsRecovered int   `json:"is_recovered"`
+	RecoverTime int64 `json:"recover_time,omitempty"`
+}
+
+// ActiveAlertBrief is a compact view of an active alert event
+type ActiveAlertBrief struct {
+	Id          int64    `json:"id"`
+	RuleId      int64    `json:"rule_id"`
+	RuleName    string   `json:"rule_name"`
+	Severity    int      `json:"severity"`
+	TargetIdent string   `json:"target_ident,omitempty"`
+	Tags        []string `json:"tags"`
+	TriggerTime int64    `json:"trigger_time"`
+}
+
+// RuleAlertStats represents alert statistics of a single rule over the review window
+type RuleAlertStats struct {
+	RuleId            int64  `json:"rule_id"`
+	RuleName          string `json:"rule_name"`
+	Severity          int    `json:"severity"`
+	Events            int    `json:"events"`
+	Recovered         int    `json:"recovered"`
+	Targets           int    `json:"targets"`
+	AvgRecoverSeconds int64  `json:"avg_recover_seconds,omitempty"`
+}
+
+// promptIDArg parses a required positive integer prompt argument
+func promptIDArg(req *mcp.GetPromptRequest, name string) (int64, error) {
+	v := strings.TrimSpace(req.Params.Arguments[name])
+	if v == "" {
+		return 0, fmt.Errorf("%s is required", name)
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return id, nil
+}
+
+// promptOptionalIDArg parses an optional positive integer prompt argument, 0 when not given
+func promptOptionalIDArg(req *mcp.GetPromptRequest, name string) (int64, error) {
+	if strings.TrimSpace(req.Params.Arguments[name]) == "" {
+		return 0, nil
+	}
+	return promptIDArg(req, name)
+}
+
+// promptText creates a user prompt message with text content
+func promptText(text string) *mcp.PromptMessage {
+	return &mcp.PromptMessage{
+		Role:    "user",
+		Content: &mcp.TextContent{Text: text},
+	}
+}
+
+// promptData creates a user prompt message with gathered data as JSON, or the error if it could not be fetched
+func promptData(title string, v any, err error) *mcp.PromptMessage {
+	if err != nil {
+		return promptText(fmt.Sprintf("%s: could not be fetched (%v)", title, err))
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return promptText(fmt.Sprintf("%s: could not be encoded (%v)", title, err))
+	}
+	return promptText(fmt.Sprintf("%s:\n```json\n%s\n```", title, data))
+}
+
+// triageAlertPrompt gathers the target and suggests muting only when the targets and mutes toolsets are enabled,
+// changes are only suggested when write tools are registered
+func triageAlertPrompt(group *toolset.ToolsetGroup, getClient client.GetClientFunc) toolset.ServerPrompt {
+	return toolset.NewServerPrompt(
+		mcp.Prompt{
+			Name:        "triage_alert",
+			Title:       "Triage Alert",
+			Description: "Triage an alert event: gathers the event, its alert rule, the target (with the targets toolset) and recent occurrences, and asks for an assessment and next steps",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "eid", Description: "Alert event ID (active or historical)", Required: true},
+			},
+		},
+		func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			eid, err := promptIDArg(req, "eid")
+			if err != nil {
+				return nil, err
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return nil, fmt.Errorf("failed to get n9e client from context")
+			}
+
+			// Active and historical events share IDs
+			active := true
+			event, err := client.DoGet[types.AlertCurEvent](c, ctx, fmt.Sprintf("/api/n9e/alert-cur-event/%d", eid), nil)
+			if err != nil && client.HTTPStatus(err) != http.StatusNotFound {
+				return nil, fmt.Errorf("failed to get alert event %d: %w", eid, err)
+			}
+			if err != nil || event.Id == 0 {
+				his, hisErr := client.DoGet[types.AlertHisEvent](c, ctx, fmt.Sprintf("/api/n9e/alert-his-event/%d", eid), nil)
+				if hisErr != nil {
+					return nil, fmt.Errorf("alert event %d not found: %w", eid, hisErr)
+				}
+				event = his.AlertCurEvent
+				active = false
+			}
+
+			state := "still firing"
+			if !active {
+				state = "no longer active"
+			}
+			withTarget := event.TargetIdent != "" && group.IsEnabled("targets")
+
+			steps := []string{
+				"Summarize what fired, where, since when and how severe it is.",
+				"Judge whether it is a real problem, a flapping or noisy rule, or a monitoring issue (e.g. stale target heartbeat), based on the rule definition and recent occurrences.",
+			}
+			if withTarget {
+				steps = append(steps, "Assess the health of the target.")
+			}
+			if group.IsReadOnly() {
+				steps = append(steps, "Recommend next steps, such as investigating with datasource queries, and changes for the user to make in Nightingale.")
+			} else {
+				nextSteps := "investigating with datasource queries, claiming the alert (claim_active_alert)"
+				if group.IsEnabled("mutes") {
+					nextSteps += ", muting it (preview_mute, then create_mute)"
+				}
+				nextSteps += " or tuning the rule (update_alert_rule)"
+				steps = append(steps, fmt.Sprintf("Recommend next steps, such as %s.", nextSteps))
+			}
+
+			var instructions strings.Builder
+			fmt.Fprintf(&instructions, "Triage Nightingale alert event %d (rule \"%s\", %s). Using the data below:\n", eid, event.RuleName, state)
+			for i, step := range steps {
+				fmt.Fprintf(&instructions, "%d. %s\n", i+1, step)
+			}
+			instructions.WriteString("Do not change anything without the user's confirmation.")
+
+			messages := []*mcp.PromptMessage{
+				promptText(instructions.String()),
+				promptData("Alert event", event, nil),
+			}
+
+			if event.RuleId > 0 {
+				rule, err := client.DoGet[types.AlertRule](c, ctx, fmt.Sprintf("/api/n9e/alert-rule/%d", event.RuleId), nil)
+				messages = append(messages, promptData("Alert rule", rule, err))
+			}
+
+			if withTarget {
+				target, err := triageTarget(ctx, c, event.TargetIdent)
+				messages = append(messages, promptData("Target", target, err))
+			}
+
+			occurrences, err := recentOccurrences(ctx, c, event)
+			messages = append(messages, promptData(fmt.Sprintf("Occurrences of this rule in business group %d over the last %d hours", event.GroupId, triageHistoryHours), occurrences, err))
+
+			return &mcp.GetPromptResult{
+				Description: fmt.Sprintf("Triage of alert event %d", eid),
+				Messages:    messages,
+			}, nil
+		},
+	)
+}
+
+// triageTarget gets the target with its health, counting its active alerts as get_target does
+func triageTarget(ctx context.Context, c *client.Client, ident string) (map[string]any, error) {
+	target, err := findTarget(ctx, c, ident)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("query", ident)
+	events, _, err := listAllActiveAlerts(ctx, c, params, maxTargetAlertsScan)
+	if err != nil {
+		return nil, err
+	}
+	activeAlerts := 0
+	for _, e := range events {
+		if e.TargetIdent == ident {
+			activeAlerts++
+		}
+	}
+
+	return map[string]any{
+		"target": target,
+		"health": targetHealth(target, time.Now(), defaultTargetStaleSeconds, defaultTargetMaxOffsetMs, activeAlerts),
+	}, nil
+}
+
+// listHistoryEvents pages through historical alert events, newest first, stopping after max events.
+// It returns the events and the total number of matching events.
+func listHistoryEvents(ctx context.Context, c *client.Client, params url.Values, max int) ([]types.AlertHisEvent, int64, error) {
+	var events []types.AlertHisEvent
+	var total int64
+	for page := 1; len(events) < max; page++ {
+		params.Set("limit", strconv.Itoa(activeAlertsPageSize))
+		params.Set("p", strconv.Itoa(page))
+		resp, err := client.DoGet[types.PageResp[types.AlertHisEvent]](c, ctx, "/api/n9e/alert-his-events/list", params)
+		if err != nil {
+			return nil, 0, err
+		}
+		total = resp.Total
+		events = append(events, resp.List...)
+		if len(resp.List) < activeAlertsPageSize || int64(len(events)) >= resp.Total {
+			break
+		}
+	}
+	return events, total, nil
+}
+
+// recentOccurrences summarizes the recent historical events of the event's rule, and the latest ones of the same alert (hash).
+// The history of the business group is scanned up to triageHistoryLimit events, when truncated the rule's count
+// only covers the latest events of the window.
+func recentOccurrences(ctx context.Context, c *client.Client, event types.AlertCurEvent) (map[string]any, error) {
+	params := url.Values{}
+	params.Set("hours", strconv.Itoa(triageHistoryHours))
+	if event.GroupId > 0 {
+		params.Set("bgid", strconv.FormatInt(event.GroupId, 10))
+	}
+	events, total, err := listHistoryEvents(ctx, c, params, triageHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleEvents := 0
+	sameAlert := make([]AlertOccurrence, 0)
+	for _, e := range events {
+		if e.RuleId != event.RuleId {
+			continue
+		}
+		ruleEvents++
+		if e.Hash == event.Hash && len(sameAlert) < triageRecentOccurrences {
+			sameAlert = append(sameAlert, AlertOccurrence{
+				Id:          e.Id,
+				TriggerTime: e.TriggerTime,
+				IsRecovered: e.IsRecovered,
+				RecoverTime: e.RecoverTime,
+			})
+		}
+	}
+
+	truncated := total > int64(len(events))
+	result := map[string]any{
+		"rule_events":       ruleEvents,
+		"same_alert_latest": sameAlert,
+		"scanned":           len(events),
+		"truncated":         truncated,
+	}
+	if truncated {
+		result["note"] = fmt.Sprintf("rule_events is a sample, counted from the latest %d of %d events of the business group", len(events), total)
+	}
+	return result, nil
+}
+
+// draftMutePrompt only asks for the mute to be created when write tools are registered
+func draftMutePrompt(group *toolset.ToolsetGroup, getClient client.GetClientFunc) toolset.ServerPrompt {
+	return toolset.NewServerPrompt(
+		mcp.Prompt{
+			Name:        "draft_mute",
+			Title:       "Draft Alert Mute",
+			Description: "Draft an alert mute rule for a business group: gathers its active alerts and existing mutes, and asks for a narrowly scoped mute to be previewed before creation",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "bgid", Description: "Business group ID", Required: true},
+				{Name: "reason", Description: "Why alerts should be muted, e.g. a maintenance window and the affected services", Required: true},
+				{Name: "datasource_id", Description: "Only mute alerts from this datasource ID"},
+				{Name: "cate", Description: "Only mute alerts of this datasource category (prometheus/host/elasticsearch/loki)"},
+				{Name: "prod", Description: "Only mute alerts of this product type (host/metric/loki/anomaly)"},
+			},
+		},
+		func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			bgid, err := promptIDArg(req, "bgid")
+			if err != nil {
+				return nil, err
+			}
+			reason := strings.TrimSpace(req.Params.Arguments["reason"])
+			if reason == "" {
+				return nil, fmt.Errorf("reason is required")
+			}
+			datasourceId, err := promptOptionalIDArg(req, "datasource_id")
+			if err != nil {
+				return nil, err
+			}
+			cate := strings.TrimSpace(req.Params.Arguments["cate"])
+			if err := toolset.ValidateCate(cate); err != nil {
+				return nil, err
+			}
+			prod := strings.TrimSpace(req.Params.Arguments["prod"])
+			if prod != "" && !toolset.ValidRuleProds[prod] {
+				return nil, fmt.Errorf("invalid prod: %s, valid values: host, metric, loki, anomaly", prod)
+			}
+
+			// The scope given by the user is applied to the gathered alerts and the drafted mute
+			params := url.Values{}
+			params.Set("bgid", strconv.FormatInt(bgid, 10))
+			var scope []string
+			if datasourceId > 0 {
+				params.Set("datasource_ids", strconv.FormatInt(datasourceId, 10))
+				scope = append(scope, fmt.Sprintf("datasource_ids [%d]", datasourceId))
+			}
+			if cate != "" {
+				params.Set("cate", cate)
+				scope = append(scope, fmt.Sprintf("cate %q", cate))
+			}
+			if prod != "" {
+				params.Set("rule_prods", prod)
+				scope = append(scope, fmt.Sprintf("prod %q", prod))
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return nil, fmt.Errorf("failed to get n9e client from context")
+			}
+
+			scopeStep := ""
+			if len(scope) > 0 {
+				scopeStep = fmt.Sprintf("\nThe mute must be limited to %s, the active alerts below are already filtered by it.", strings.Join(scope, ", "))
+			}
+
+			lastStep := "5. Show the final create_mute parameters, with the reason as cause, and only call create_mute after the user confirms."
+			if group.IsReadOnly() {
+				lastStep = "5. Show the final mute parameters, with the reason as cause, for the user to create in Nightingale. Write tools are disabled, do not try to create it."
+			}
+
+			messages := []*mcp.PromptMessage{
+				promptText(fmt.Sprintf(`Draft an alert mute for business group %d. Reason: %s%s
+
+Using the data below:
+1. Pick tag filters that match only the alerts affected by the reason, prefer exact (==) or "in" matches over regular expressions.
+2. Pick a start and end time covering the reason (e.g. the maintenance window), as short as reasonable. Use a periodic mute only for recurring windows.
+3. Check that an existing mute does not already cover it.
+4. Run preview_mute with the draft and show which active alerts it would match.
+%s`, bgid, reason, scopeStep, lastStep)),
+			}
+
+			events, truncated, err := listAllActiveAlerts(ctx, c, params, draftMuteAlertsLimit)
+			var alerts any
+			if err == nil {
+				briefs := make([]ActiveAlertBrief, 0, len(events))
+				for _, e := range events {
+					briefs = append(briefs, ActiveAlertBrief{
+						Id:          e.Id,
+						RuleId:      e.RuleId,
+						RuleName:    e.RuleName,
+						Severity:    e.Severity,
+						TargetIdent: e.TargetIdent,
+						Tags:        e.Tags,
+						TriggerTime: e.TriggerTime,
+					})
+				}
+				alerts = map[string]any{"total": len(briefs), "truncated": truncated, "alerts": briefs}
+			}
+			messages = append(messages, promptData("Active alerts of the business group", alerts, err))
+
+			mutes, err := client.DoGet[[]types.AlertMute](c, ctx, fmt.Sprintf("/api/n9e/busi-group/%d/alert-mutes", bgid), nil)
+			messages = append(messages, promptData("Existing mutes of the business group", mutes, err))
+
+			return &mcp.GetPromptResult{
+				Description: fmt.Sprintf("Draft a mute for business group %d", bgid),
+				Messages:    messages,
+			}, nil
+		},
+	)
+}
+
+func weeklyAlertReviewPrompt(getClient client.GetClientFunc) toolset.ServerPrompt {
+	return toolset.NewServerPrompt(
+		mcp.Prompt{
+			Name:        "weekly_alert_review",
+			Title:       "Weekly Alert Review",
+			Description: "Review the alerts of a business group over the last 7 days: gathers per-rule alert statistics and asks for noisy rules and tuning suggestions",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "bgid", Description: "Business group ID", Required: true},
+				{Name: "rule_id", Description: "Only review this alert rule of the business group"},
+			},
+		},
+		func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			bgid, err := promptIDArg(req, "bgid")
+			if err != nil {
+				return nil, err
+			}
+			ruleId, err := promptOptionalIDArg(req, "rule_id")
+			if err != nil {
+				return nil, err
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return nil, fmt.Errorf("failed to get n9e client from context")
+			}
+
+			if ruleId > 0 {
+				rule, err := client.DoGet[types.AlertRule](c, ctx, fmt.Sprintf("/api/n9e/alert-rule/%d", ruleId), nil)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get alert rule %d: %w", ruleId, err)
+				}
+				if rule.GroupId != bgid {
+					return nil, fmt.Errorf("alert rule %d does not belong to business group %d", ruleId, bgid)
+				}
+
+				stats, err := weeklyRuleStats(ctx, c, bgid, ruleId)
+				return &mcp.GetPromptResult{
+					Description: fmt.Sprintf("Weekly alert review of rule %d in business group %d", ruleId, bgid),
+					Messages: []*mcp.PromptMessage{
+						promptText(fmt.Sprintf(`Write a weekly alert review of alert rule %d ("%s") in business group %d, covering the last 7 days. Using the data below:
+1. Give the alert volume of the rule, and its share of the business group's alerts.
+2. Judge whether the rule is noisy or flapping (many events recovering quickly), or has alerts that never recover.
+3. Suggest concrete changes to the rule definition: threshold, for-duration, severity, a mute, or retiring the rule.
+This is a review only, do not change anything.`, ruleId, rule.Name, bgid)),
+						promptData("Alert rule", rule, nil),
+						promptData("Alert statistics of the rule and the business group", stats, err),
+					},
+				}, nil
+			}
+
+			messages := []*mcp.PromptMessage{
+				promptText(fmt.Sprintf(`Write a weekly alert review for business group %d, covering the last 7 days. Using the data below:
+1. Give the overall alert volume and the rules producing most of it.
+2. Point out noisy or flapping rules (many events recovering quickly), and alerts that never recover.
+3. For each problematic rule, suggest a concrete change: threshold, for-duration, severity, a mute, or retiring the rule. Use get_alert_rule to check rule details where needed.
+4. End with a short prioritized action list.
+This is a review only, do not change anything.`, bgid)),
+			}
+
+			stats, err := weeklyRuleStats(ctx, c, bgid, 0)
+			messages = append(messages, promptData("Alert statistics per rule, most events first", stats, err))
+
+			return &mcp.GetPromptResult{
+				Description: fmt.Sprintf("Weekly alert review of business group %d", bgid),
+				Messages:    messages,
+			}, nil
+		},
+	)
+}
+
+// weeklyRuleStats aggregates the historical alert events of the last 7 days per rule.
+// When ruleId is set, the statistics of that rule are included even if it is not a top rule.
+func weeklyRuleStats(ctx context.Context, c *client.Client, bgid, ruleId int64) (map[string]any, error) {
+	params := url.Values{}
+	params.Set("bgid", strconv.FormatInt(bgid, 10))
+	params.Set("hours", strconv.Itoa(7*24))
+
+	events, total, err := listHistoryEvents(ctx, c, params, weeklyReviewHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	byRule := make(map[int64]*RuleAlertStats)
+	targets := make(map[int64]map[string]bool)
+	recoverSeconds := make(map[int64]int64)
+	for _, e := range events {
+		s, ok := byRule[e.RuleId]
+		if !ok {
+			s = &RuleAlertStats{RuleId: e.RuleId, RuleName: e.RuleName, Severity: e.Severity}
+			byRule[e.RuleId] = s
+			targets[e.RuleId] = make(map[string]bool)
+		}
+		s.Events++
+		if e.TargetIdent != "" {
+			targets[e.RuleId][e.TargetIdent] = true
+		}
+		if e.IsRecovered == 1 && e.RecoverTime >= e.TriggerTime {
+			s.Recovered++
+			recoverSeconds[e.RuleId] += e.RecoverTime - e.TriggerTime
+		}
+	}
+
+	rules := make([]RuleAlertStats, 0, len(byRule))
+	for id, s := range byRule {
+		s.Targets = len(targets[id])
+		if s.Recovered > 0 {
+			s.AvgRecoverSeconds = recoverSeconds[id] / int64(s.Recovered)
+		}
+		rules = append(rules, *s)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Events != rules[j].Events {
+			return rules[i].Events > rules[j].Events
+		}
+		return rules[i].RuleId < rules[j].RuleId
+	})
+	if len(rules) > weeklyReviewTopRules {
+		rules = rules[:weeklyReviewTopRules]
+	}
+
+	result := map[string]any{
+		"total_events": total,
+		"scanned":      len(events),
+		"truncated":    total > int64(len(events)),
+		"rule_count":   len(byRule),
+		"top_rules":    rules,
+	}
+	if ruleId > 0 {
+		rule := RuleAlertStats{RuleId: ruleId}
+		if s, ok := byRule[ruleId]; ok {
+			rule = *s
+		}
+		result["rule"] = rule
+	}
+	return result, nil
+}
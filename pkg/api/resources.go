@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/n9e/n9e-mcp-server/pkg/client"
+	"github.com/n9e/n9e-mcp-server/pkg/toolset"
+	"github.com/n9e/n9e-mcp-server/pkg/types"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// resourceFetchFunc fetches the resource identified by id
+type resourceFetchFunc func(ctx context.Context, c *client.Client, id int64) (any, error)
+
+// makeResourceHandler creates a resource handler for URIs of the form prefix{id}suffix
+func makeResourceHandler(getClient client.GetClientFunc, prefix, suffix string, fetch resourceFetchFunc) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		id, err := resourceID(uri, prefix, suffix)
+		if err != nil {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+
+		c := getClient(ctx)
+		if c == nil {
+			return nil, fmt.Errorf("failed to get n9e client from context")
+		}
+
+		result, err := fetch(ctx, c, id)
+		if err != nil {
+			return nil, err
+		}
+		return toolset.MarshalResource(uri, result)
+	}
+}
+
+// resourceID extracts the positive numeric ID between prefix and suffix of a resource URI
+func resourceID(uri, prefix, suffix string) (int64, error) {
+	if !strings.HasPrefix(uri, prefix) || !strings.HasSuffix(uri, suffix) || len(uri) < len(prefix)+len(suffix) {
+		return 0, fmt.Errorf("invalid resource uri: %s", uri)
+	}
+	id, err := strconv.ParseInt(uri[len(prefix):len(uri)-len(suffix)], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid resource uri: %s", uri)
+	}
+	return id, nil
+}
+
+// ListBusiGroupResources lists the business groups accessible to the current user as resources
+func ListBusiGroupResources(ctx context.Context, c *client.Client) ([]*mcp.Resource, error) {
+	groups, err := client.DoGet[[]types.BusiGroup](c, ctx, "/api/n9e/busi-groups", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]*mcp.Resource, 0, len(groups))
+	for _, bg := range groups {
+		resources = append(resources, &mcp.Resource{
+			URI:         fmt.Sprintf("n9e://busi-group/%d", bg.Id),
+			Name:        bg.Name,
+			Title:       fmt.Sprintf("Business group %s", bg.Name),
+			Description: fmt.Sprintf("Business group %s (ID %d), its alert mutes are available at n9e://busi-group/%d/mutes", bg.Name, bg.Id, bg.Id),
+			MIMEType:    "application/json",
+		})
+	}
+	return resources, nil
+}
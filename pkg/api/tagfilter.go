@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/n9e/n9e-mcp-server/pkg/types"
+)
+
+// Tag filter operators
+const (
+	tagFuncEqual     = "=="
+	tagFuncNotEqual  = "!="
+	tagFuncIn        = "in"
+	tagFuncNotIn     = "not in"
+	tagFuncRegexp    = "=~"
+	tagFuncNotRegexp = "!~"
+)
+
+// compiledTagFilter is a TagFilter with its value set or regexp prepared
+type compiledTagFilter struct {
+	types.TagFilter
+	values map[string]struct{}
+	regexp *regexp.Regexp
+}
+
+// tagMatcher matches tags against a list of tag filters, same as Nightingale does for mutes and subscriptions
+type tagMatcher struct {
+	filters []compiledTagFilter
+}
+
+// newTagMatcher compiles tag filters into a tagMatcher
+func newTagMatcher(filters []types.TagFilter) (*tagMatcher, error) {
+	m := &tagMatcher{filters: make([]compiledTagFilter, 0, len(filters))}
+	for _, f := range filters {
+		if f.Key == "" {
+			return nil, fmt.Errorf("tag filter key is required")
+		}
+
+		cf := compiledTagFilter{TagFilter: f}
+		switch f.Func {
+		case tagFuncEqual, tagFuncNotEqual:
+		case tagFuncIn, tagFuncNotIn:
+			// 'in' and 'not in' take space-separated values
+			cf.values = make(map[string]struct{})
+			for _, v := range strings.Fields(f.Value) {
+				cf.values[v] = struct{}{}
+			}
+		case tagFuncRegexp, tagFuncNotRegexp:
+			re, err := regexp.Compile(f.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regexp of tag filter %s: %w", f.Key, err)
+			}
+			cf.regexp = re
+		default:
+			return nil, fmt.Errorf("invalid tag filter func: %s, valid values: ==, !=, in, not in, =~, !~", f.Func)
+		}
+		m.filters = append(m.filters, cf)
+	}
+	return m, nil
+}
+
+// Match reports whether tags satisfy all filters.
+// A missing tag only satisfies the negative operators (!=, not in, !~).
+func (m *tagMatcher) Match(tags map[string]string) bool {
+	for _, f := range m.filters {
+		value, ok := tags[f.Key]
+		if !ok {
+			if f.Func == tagFuncNotEqual || f.Func == tagFuncNotIn || f.Func == tagFuncNotRegexp {
+				continue
+			}
+			return false
+		}
+		if !f.match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// match checks a single tag value
+func (f compiledTagFilter) match(value string) bool {
+	switch f.Func {
+	case tagFuncEqual:
+		return strings.TrimSpace(f.Value) == strings.TrimSpace(value)
+	case tagFuncNotEqual:
+		return strings.TrimSpace(f.Value) != strings.TrimSpace(value)
+	case tagFuncIn:
+		_, ok := f.values[value]
+		return ok
+	case tagFuncNotIn:
+		_, ok := f.values[value]
+		return !ok
+	case tagFuncRegexp:
+		return f.regexp.MatchString(value)
+	case tagFuncNotRegexp:
+		return !f.regexp.MatchString(value)
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/n9e/n9e-mcp-server/pkg/types"
+)
+
+func TestTagMatcher(t *testing.T) {
+	tags := map[string]string{"env": "prod", "region": "us-east-1", "service": "api"}
+
+	tests := []struct {
+		name    string
+		filters []types.TagFilter
+		want    bool
+	}{
+		{"no filters", nil, true},
+		{"equal", []types.TagFilter{{Key: "env", Func: "==", Value: "prod"}}, true},
+		{"equal trims spaces", []types.TagFilter{{Key: "env", Func: "==", Value: " prod "}}, true},
+		{"equal mismatch", []types.TagFilter{{Key: "env", Func: "==", Value: "test"}}, false},
+		{"not equal", []types.TagFilter{{Key: "env", Func: "!=", Value: "test"}}, true},
+		{"not equal mismatch", []types.TagFilter{{Key: "env", Func: "!=", Value: "prod"}}, false},
+		{"in", []types.TagFilter{{Key: "service", Func: "in", Value: "web api"}}, true},
+		{"in mismatch", []types.TagFilter{{Key: "service", Func: "in", Value: "web db"}}, false},
+		{"not in", []types.TagFilter{{Key: "service", Func: "not in", Value: "web db"}}, true},
+		{"not in mismatch", []types.TagFilter{{Key: "service", Func: "not in", Value: "api db"}}, false},
+		{"regexp", []types.TagFilter{{Key: "region", Func: "=~", Value: "^us-"}}, true},
+		{"regexp mismatch", []types.TagFilter{{Key: "region", Func: "=~", Value: "^eu-"}}, false},
+		{"not regexp", []types.TagFilter{{Key: "region", Func: "!~", Value: "^eu-"}}, true},
+		{"not regexp mismatch", []types.TagFilter{{Key: "region", Func: "!~", Value: "^us-"}}, false},
+		{"missing tag equal", []types.TagFilter{{Key: "team", Func: "==", Value: "a"}}, false},
+		{"missing tag in", []types.TagFilter{{Key: "team", Func: "in", Value: "a b"}}, false},
+		{"missing tag regexp", []types.TagFilter{{Key: "team", Func: "=~", Value: ".*"}}, false},
+		{"missing tag not equal", []types.TagFilter{{Key: "team", Func: "!=", Value: "a"}}, true},
+		{"missing tag not in", []types.TagFilter{{Key: "team", Func: "not in", Value: "a b"}}, true},
+		{"missing tag not regexp", []types.TagFilter{{Key: "team", Func: "!~", Value: "a"}}, true},
+		{"all filters must match", []types.TagFilter{
+			{Key: "env", Func: "==", Value: "prod"},
+			{Key: "service", Func: "==", Value: "web"},
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newTagMatcher(tt.filters)
+			if err != nil {
+				t.Fatalf("newTagMatcher() error = %v", err)
+			}
+			if got := m.Match(tags); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTagMatcherErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []types.TagFilter
+	}{
+		{"missing key", []types.TagFilter{{Func: "==", Value: "a"}}},
+		{"invalid func", []types.TagFilter{{Key: "env", Func: "~", Value: "a"}}},
+		{"invalid regexp", []types.TagFilter{{Key: "env", Func: "=~", Value: "("}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newTagMatcher(tt.filters); err == nil {
+				t.Error("newTagMatcher() error = nil, want error")
+			}
+		})
+	}
+}
@@ -2,8 +2,12 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/n9e/n9e-mcp-server/pkg/client"
 	"github.com/n9e/n9e-mcp-server/pkg/toolset"
@@ -23,12 +27,102 @@ type ListTargetsInput struct {
 	DatasourceIds string `json:"datasource_ids,omitempty"`
 }
 
+// GetTargetInput represents get target health parameters
+type GetTargetInput struct {
+	Ident        string `json:"ident"`
+	StaleSeconds int64  `json:"stale_seconds,omitempty"`
+	MaxOffsetMs  int64  `json:"max_offset_ms,omitempty"`
+	AlertsLimit  int    `json:"alerts_limit,omitempty"`
+}
+
+// TargetHealth represents facts derived from a target's heartbeat
+type TargetHealth struct {
+	Healthy          bool     `json:"healthy"`
+	Issues           []string `json:"issues,omitempty"`
+	SecondsSinceSeen int64    `json:"seconds_since_seen"`
+	HeartbeatStale   bool     `json:"heartbeat_stale"`
+	OffsetMs         int64    `json:"offset_ms"`
+	ClockSkew        bool     `json:"clock_skew"`
+	AgentVersion     string   `json:"agent_version"`
+	OS               string   `json:"os"`
+	Arch             string   `json:"arch"`
+	ActiveAlerts     int      `json:"active_alerts"`
+}
+
+// TargetAlert represents an active alert event of a target
+type TargetAlert struct {
+	Id           int64  `json:"id"`
+	RuleId       int64  `json:"rule_id"`
+	RuleName     string `json:"rule_name"`
+	Severity     int    `json:"severity"`
+	TriggerTime  int64  `json:"trigger_time"`
+	TriggerValue string `json:"trigger_value"`
+}
+
+// TargetDetail represents a target with its health and active alerts
+type TargetDetail struct {
+	Target       types.Target  `json:"target"`
+	Health       TargetHealth  `json:"health"`
+	ActiveAlerts []TargetAlert `json:"active_alerts"`
+	Truncated    bool          `json:"truncated,omitempty"`
+}
+
+// TargetTagsInput represents add/remove target tags parameters
+type TargetTagsInput struct {
+	Idents []string `json:"idents"`
+	Tags   []string `json:"tags"`
+}
+
+// UpdateTargetsBusiGroupInput represents bind targets to business group parameters
+type UpdateTargetsBusiGroupInput struct {
+	Idents  []string `json:"idents"`
+	GroupId int64    `json:"group_id"`
+}
+
+// UpdateTargetsNoteInput represents update targets note parameters
+type UpdateTargetsNoteInput struct {
+	Idents []string `json:"idents"`
+	Note   string   `json:"note"`
+}
+
+// DeleteTargetsInput represents delete targets parameters
+type DeleteTargetsInput struct {
+	Idents []string `json:"idents"`
+}
+
+// TargetsOpResult represents per-ident result of a bulk target operation
+type TargetsOpResult struct {
+	Succeeded []string          `json:"succeeded"`
+	Failed    map[string]string `json:"failed,omitempty"`
+	Message   string            `json:"message"`
+}
+
+const (
+	maxTargetsPerOp   = 500
+	targetsOpParallel = 8
+
+	defaultTargetStaleSeconds = 120
+	defaultTargetMaxOffsetMs  = 2000
+	defaultTargetAlertsLimit  = 50
+	maxTargetAlertsScan       = 1000
+	findTargetPageSize        = 100
+)
+
 // RegisterTargetsToolset registers targets toolset
 func RegisterTargetsToolset(group *toolset.ToolsetGroup, getClient client.GetClientFunc) {
 	ts := toolset.NewToolset("targets", "Target/Host management tools for viewing monitored objects")
 
 	ts.AddReadTools(
 		listTargetsTool(getClient),
+		getTargetTool(getClient),
+	)
+
+	ts.AddWriteTools(
+		addTargetTagsTool(getClient),
+		removeTargetTagsTool(getClient),
+		updateTargetsBusiGroupTool(getClient),
+		updateTargetsNoteTool(getClient),
+		deleteTargetsTool(getClient),
 	)
 
 	group.AddToolset(ts)
@@ -72,6 +166,7 @@ func listTargetsTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.PageResp[types.Target]](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListTargetsInput) (*mcp.CallToolResult, error) {
 			if err := toolset.ValidatePagination(input.Limit, input.Page); err != nil {
@@ -112,3 +207,470 @@ func listTargetsTool(getClient client.GetClientFunc) toolset.ServerTool {
 		}),
 	)
 }
+
+func getTargetTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "get_target",
+			Description: "Get a target/host by ident with health facts (heartbeat age, clock skew, agent version, OS/arch) and its active alerts",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Target",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"ident"},
+				Properties: map[string]*jsonschema.Schema{
+					"ident": {
+						Type:        "string",
+						Description: "Target ident",
+					},
+					"stale_seconds": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Heartbeat older than this many seconds is stale (default %d)", defaultTargetStaleSeconds),
+					},
+					"max_offset_ms": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Clock offset beyond this many milliseconds is reported as skew (default %d)", defaultTargetMaxOffsetMs),
+					},
+					"alerts_limit": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Max active alerts to return (default %d)", defaultTargetAlertsLimit),
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[TargetDetail](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetTargetInput) (*mcp.CallToolResult, error) {
+			ident := strings.TrimSpace(input.Ident)
+			if ident == "" {
+				return toolset.NewToolResultError("ident is required"), nil
+			}
+			if input.StaleSeconds < 0 || input.MaxOffsetMs < 0 {
+				return toolset.NewToolResultError("stale_seconds and max_offset_ms must be >= 0"), nil
+			}
+			if err := toolset.ValidatePagination(input.AlertsLimit, 0); err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			target, err := findTarget(ctx, c, ident)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			// The list API searches by keyword, keep only events of this target
+			params := url.Values{}
+			params.Set("query", ident)
+			events, truncated, err := listAllActiveAlerts(ctx, c, params, maxTargetAlertsScan)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			limit := input.AlertsLimit
+			if limit == 0 {
+				limit = defaultTargetAlertsLimit
+			}
+
+			detail := TargetDetail{
+				Target:       *target,
+				ActiveAlerts: []TargetAlert{},
+				Truncated:    truncated,
+			}
+			count := 0
+			for _, e := range events {
+				if e.TargetIdent != ident {
+					continue
+				}
+				count++
+				if len(detail.ActiveAlerts) < limit {
+					detail.ActiveAlerts = append(detail.ActiveAlerts, TargetAlert{
+						Id:           e.Id,
+						RuleId:       e.RuleId,
+						RuleName:     e.RuleName,
+						Severity:     e.Severity,
+						TriggerTime:  e.TriggerTime,
+						TriggerValue: e.TriggerValue,
+					})
+				}
+			}
+
+			staleSeconds := input.StaleSeconds
+			if staleSeconds == 0 {
+				staleSeconds = defaultTargetStaleSeconds
+			}
+			maxOffsetMs := input.MaxOffsetMs
+			if maxOffsetMs == 0 {
+				maxOffsetMs = defaultTargetMaxOffsetMs
+			}
+			detail.Health = targetHealth(target, time.Now(), staleSeconds, maxOffsetMs, count)
+
+			return toolset.MarshalResult(detail), nil
+		}),
+	)
+}
+
+// findTarget finds the target with exactly the given ident.
+// The targets API only searches by substring, so all matching pages are scanned.
+func findTarget(ctx context.Context, c *client.Client, ident string) (*types.Target, error) {
+	params := url.Values{}
+	params.Set("query", ident)
+	params.Set("limit", strconv.Itoa(findTargetPageSize))
+
+	for page, scanned := 1, 0; ; page++ {
+		params.Set("p", strconv.Itoa(page))
+		result, err := client.DoGet[types.PageResp[types.Target]](c, ctx, "/api/n9e/targets", params)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result.List {
+			if result.List[i].Ident == ident {
+				return &result.List[i], nil
+			}
+		}
+
+		scanned += len(result.List)
+		if len(result.List) < findTargetPageSize || int64(scanned) >= result.Total {
+			return nil, fmt.Errorf("target not found: %s", ident)
+		}
+	}
+}
+
+// targetHealth derives health facts from the target's heartbeat (UpdateAt, in seconds)
+// and clock offset reported by the agent (Offset, in milliseconds)
+func targetHealth(target *types.Target, now time.Time, staleSeconds, maxOffsetMs int64, activeAlerts int) TargetHealth {
+	h := TargetHealth{
+		OffsetMs:     target.Offset,
+		AgentVersion: target.AgentVersion,
+		OS:           target.OS,
+		Arch:         target.Arch,
+		ActiveAlerts: activeAlerts,
+	}
+
+	if target.UpdateAt > 0 {
+		h.SecondsSinceSeen = now.Unix() - target.UpdateAt
+		h.HeartbeatStale = h.SecondsSinceSeen > staleSeconds
+	} else {
+		h.SecondsSinceSeen = -1
+		h.HeartbeatStale = true
+	}
+	if h.HeartbeatStale {
+		if target.UpdateAt > 0 {
+			h.Issues = append(h.Issues, fmt.Sprintf("no heartbeat for %ds", h.SecondsSinceSeen))
+		} else {
+			h.Issues = append(h.Issues, "never reported a heartbeat")
+		}
+	}
+
+	offset := target.Offset
+	if offset < 0 {
+		offset = -offset
+	}
+	h.ClockSkew = offset > maxOffsetMs
+	if h.ClockSkew {
+		h.Issues = append(h.Issues, fmt.Sprintf("clock offset %dms exceeds %dms", target.Offset, maxOffsetMs))
+	}
+
+	if activeAlerts > 0 {
+		h.Issues = append(h.Issues, fmt.Sprintf("%d active alert(s)", activeAlerts))
+	}
+
+	h.Healthy = !h.HeartbeatStale && !h.ClockSkew && activeAlerts == 0
+	return h
+}
+
+// identsSchema describes the bulk ident list parameter
+func identsSchema(description string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "array",
+		Description: description,
+		Items:       &jsonschema.Schema{Type: "string"},
+	}
+}
+
+// targetTagsSchema describes the add/remove target tags parameters
+func targetTagsSchema(tagsDescription string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"idents", "tags"},
+		Properties: map[string]*jsonschema.Schema{
+			"idents": identsSchema("Target idents"),
+			"tags": {
+				Type:        "array",
+				Description: tagsDescription,
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+		},
+	}
+}
+
+func addTargetTagsTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "add_target_tags",
+			Description: "Add custom tags to targets/hosts. Tags show up in the target's tags_map and are attached to its series and alerts.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Add Target Tags",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema:  targetTagsSchema("Tags in key=value format"),
+			OutputSchema: toolset.OutputSchema[TargetsOpResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input TargetTagsInput) (*mcp.CallToolResult, error) {
+			return runTargetTagsOp(ctx, getClient, input, "POST", "tags added")
+		}),
+	)
+}
+
+func removeTargetTagsTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "remove_target_tags",
+			Description: "Remove custom tags from targets/hosts",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Remove Target Tags",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(true),
+				IdempotentHint:  true,
+			},
+			InputSchema:  targetTagsSchema("Tags to remove in key=value format"),
+			OutputSchema: toolset.OutputSchema[TargetsOpResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input TargetTagsInput) (*mcp.CallToolResult, error) {
+			return runTargetTagsOp(ctx, getClient, input, "DELETE", "tags removed")
+		}),
+	)
+}
+
+// runTargetTagsOp adds (POST) or removes (DELETE) target tags
+func runTargetTagsOp(ctx context.Context, getClient client.GetClientFunc, input TargetTagsInput, method, done string) (*mcp.CallToolResult, error) {
+	idents, err := normalizeIdents(input.Idents)
+	if err != nil {
+		return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+	}
+	if len(input.Tags) == 0 {
+		return toolset.NewToolResultError("tags is required"), nil
+	}
+	for _, tag := range input.Tags {
+		if k, _, ok := strings.Cut(tag, "="); !ok || strings.TrimSpace(k) == "" {
+			return toolset.NewToolResultError(fmt.Sprintf("invalid tag: %q, must be key=value", tag)), nil
+		}
+	}
+
+	c := getClient(ctx)
+	if c == nil {
+		return toolset.NewToolResultError("failed to get n9e client from context"), nil
+	}
+
+	result := applyTargetsOp(idents, func(ident string) error {
+		body := map[string]any{"idents": []string{ident}, "tags": input.Tags}
+		if method == "DELETE" {
+			_, err := client.DoDelete[any](c, ctx, "/api/n9e/targets/tags", body)
+			return err
+		}
+		_, err := client.DoPost[any](c, ctx, "/api/n9e/targets/tags", body)
+		return err
+	}, done)
+
+	return toolset.MarshalResult(result), nil
+}
+
+func updateTargetsBusiGroupTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "update_targets_busi_group",
+			Description: "Move targets/hosts to a business group, or unbind them from their business group with group_id=0",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Update Targets Business Group",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"idents", "group_id"},
+				Properties: map[string]*jsonschema.Schema{
+					"idents": identsSchema("Target idents"),
+					"group_id": {
+						Type:        "integer",
+						Description: "Business group ID to bind to (0 to unbind)",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[TargetsOpResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UpdateTargetsBusiGroupInput) (*mcp.CallToolResult, error) {
+			idents, err := normalizeIdents(input.Idents)
+			if err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+			if input.GroupId < 0 {
+				return toolset.NewToolResultError("group_id must be >= 0"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			done := fmt.Sprintf("bound to business group %d", input.GroupId)
+			if input.GroupId == 0 {
+				done = "unbound from business group"
+			}
+			result := applyTargetsOp(idents, func(ident string) error {
+				_, err := client.DoPut[any](c, ctx, "/api/n9e/targets/bgid", map[string]any{"idents": []string{ident}, "bgid": input.GroupId})
+				return err
+			}, done)
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+func updateTargetsNoteTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "update_targets_note",
+			Description: "Set the note of targets/hosts (empty note clears it)",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Update Targets Note",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"idents", "note"},
+				Properties: map[string]*jsonschema.Schema{
+					"idents": identsSchema("Target idents"),
+					"note": {
+						Type:        "string",
+						Description: "Note to set",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[TargetsOpResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UpdateTargetsNoteInput) (*mcp.CallToolResult, error) {
+			idents, err := normalizeIdents(input.Idents)
+			if err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			result := applyTargetsOp(idents, func(ident string) error {
+				_, err := client.DoPut[any](c, ctx, "/api/n9e/targets/note", map[string]any{"idents": []string{ident}, "note": input.Note})
+				return err
+			}, "note updated")
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+func deleteTargetsTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "delete_targets",
+			Description: "Delete decommissioned targets/hosts. A target that still reports data will be registered again.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Delete Targets",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(true),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"idents"},
+				Properties: map[string]*jsonschema.Schema{
+					"idents": identsSchema("Target idents to delete"),
+				},
+			},
+			OutputSchema: toolset.OutputSchema[TargetsOpResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input DeleteTargetsInput) (*mcp.CallToolResult, error) {
+			idents, err := normalizeIdents(input.Idents)
+			if err != nil {
+				return toolset.NewToolResultError(fmt.Sprintf("invalid input: %v", err)), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			result := applyTargetsOp(idents, func(ident string) error {
+				_, err := client.DoDelete[any](c, ctx, "/api/n9e/targets", map[string]any{"idents": []string{ident}})
+				return err
+			}, "deleted")
+
+			return toolset.MarshalResult(result), nil
+		}),
+	)
+}
+
+// normalizeIdents trims and deduplicates idents, keeping the given order
+func normalizeIdents(idents []string) ([]string, error) {
+	seen := make(map[string]bool, len(idents))
+	result := make([]string, 0, len(idents))
+	for _, ident := range idents {
+		ident = strings.TrimSpace(ident)
+		if ident == "" || seen[ident] {
+			continue
+		}
+		seen[ident] = true
+		result = append(result, ident)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("idents is required")
+	}
+	if len(result) > maxTargetsPerOp {
+		return nil, fmt.Errorf("too many idents: %d, at most %d per call", len(result), maxTargetsPerOp)
+	}
+	return result, nil
+}
+
+// applyTargetsOp runs op for each ident separately, so that one ident failing
+// (e.g. no permission on its business group) does not fail the others
+func applyTargetsOp(idents []string, op func(ident string) error, done string) TargetsOpResult {
+	errs := make([]error, len(idents))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, targetsOpParallel)
+	for i, ident := range idents {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ident string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = op(ident)
+		}(i, ident)
+	}
+	wg.Wait()
+
+	result := TargetsOpResult{Succeeded: []string{}}
+	for i, ident := range idents {
+		if errs[i] != nil {
+			if result.Failed == nil {
+				result.Failed = make(map[string]string)
+			}
+			result.Failed[ident] = errs[i].Error()
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, ident)
+	}
+	result.Message = fmt.Sprintf("%d of %d target(s) %s", len(result.Succeeded), len(idents), done)
+
+	return result
+}
@@ -16,6 +16,7 @@ func DefaultToolsetGroup(getClient client.GetClientFunc, readOnly bool) *toolset
 	RegisterMutesToolset(group, getClient)
 	RegisterBusiGroupsToolset(group, getClient)
 	RegisterNotifyRulesToolset(group, getClient)
+	RegisterNotifyChannelsToolset(group, getClient)
 	RegisterAlertSubscribesToolset(group, getClient)
 	RegisterEventPipelinesToolset(group, getClient)
 	RegisterUsersToolset(group, getClient)
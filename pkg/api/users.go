@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/n9e/n9e-mcp-server/pkg/client"
 	"github.com/n9e/n9e-mcp-server/pkg/toolset"
@@ -37,6 +39,66 @@ type GetUserGroupInput struct {
 	GroupId int64 `json:"id"`
 }
 
+// CreateUserInput represents create user parameters
+type CreateUserInput struct {
+	Username string            `json:"username"`
+	Password string            `json:"password"`
+	Nickname string            `json:"nickname,omitempty"`
+	Phone    string            `json:"phone,omitempty"`
+	Email    string            `json:"email,omitempty"`
+	Roles    []string          `json:"roles"`
+	Contacts map[string]string `json:"contacts,omitempty"`
+}
+
+// UpdateUserInput represents update user parameters, omitted fields are unchanged
+type UpdateUserInput struct {
+	UserId   int64             `json:"id"`
+	Nickname *string           `json:"nickname,omitempty"`
+	Phone    *string           `json:"phone,omitempty"`
+	Email    *string           `json:"email,omitempty"`
+	Roles    []string          `json:"roles,omitempty"`
+	Contacts map[string]string `json:"contacts,omitempty"`
+	Confirm  bool              `json:"confirm,omitempty"`
+}
+
+// DisableUserInput represents disable user parameters
+type DisableUserInput struct {
+	UserId  int64  `json:"id"`
+	Role    string `json:"role,omitempty"`
+	Confirm bool   `json:"confirm"`
+}
+
+// CreateUserGroupInput represents create user group parameters
+type CreateUserGroupInput struct {
+	Name string `json:"name"`
+	Note string `json:"note,omitempty"`
+}
+
+// UpdateUserGroupInput represents update user group parameters, omitted fields are unchanged
+type UpdateUserGroupInput struct {
+	GroupId int64   `json:"id"`
+	Name    *string `json:"name,omitempty"`
+	Note    *string `json:"note,omitempty"`
+}
+
+// UserGroupMembersInput represents add/remove user group members parameters
+type UserGroupMembersInput struct {
+	GroupId int64   `json:"id"`
+	UserIds []int64 `json:"user_ids"`
+}
+
+// UserActionResult represents the result of a user write operation
+type UserActionResult struct {
+	Id           int64    `json:"id,omitempty"`
+	Username     string   `json:"username,omitempty"`
+	Roles        []string `json:"roles,omitempty"`
+	RevokedRoles []string `json:"revoked_roles,omitempty"`
+	Message      string   `json:"message"`
+}
+
+// defaultDisabledUserRole is the role disabled users are restricted to, Nightingale ships it as read-only
+const defaultDisabledUserRole = "Guest"
+
 // RegisterUsersToolset registers users and user groups toolset
 func RegisterUsersToolset(group *toolset.ToolsetGroup, getClient client.GetClientFunc) {
 	ts := toolset.NewToolset("users", "User and user group management tools")
@@ -48,6 +110,16 @@ func RegisterUsersToolset(group *toolset.ToolsetGroup, getClient client.GetClien
 		getUserGroupTool(getClient),
 	)
 
+	ts.AddWriteTools(
+		createUserTool(getClient),
+		updateUserTool(getClient),
+		disableUserTool(getClient),
+		createUserGroupTool(getClient),
+		updateUserGroupTool(getClient),
+		addUserGroupMembersTool(getClient),
+		removeUserGroupMembersTool(getClient),
+	)
+
 	group.AddToolset(ts)
 }
 
@@ -77,6 +149,7 @@ func listUsersTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.PageResp[types.User]](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListUsersInput) (*mcp.CallToolResult, error) {
 			c := getClient(ctx)
@@ -124,6 +197,7 @@ func getUserTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.User](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetUserInput) (*mcp.CallToolResult, error) {
 			if input.UserId <= 0 {
@@ -168,6 +242,7 @@ func listUserGroupsTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[[]types.UserGroup](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input ListUserGroupsInput) (*mcp.CallToolResult, error) {
 			c := getClient(ctx)
@@ -212,6 +287,7 @@ func getUserGroupTool(getClient client.GetClientFunc) toolset.ServerTool {
 					},
 				},
 			},
+			OutputSchema: toolset.OutputSchema[types.UserGroupDetail](),
 		},
 		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input GetUserGroupInput) (*mcp.CallToolResult, error) {
 			if input.GroupId <= 0 {
@@ -233,3 +309,533 @@ func getUserGroupTool(getClient client.GetClientFunc) toolset.ServerTool {
 		}),
 	)
 }
+
+// contactsSchema describes the user contacts parameter
+func contactsSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:                 "object",
+		Description:          "Contacts used by notify channels, e.g. {\"dingtalk_robot_token\": \"...\", \"wecom_robot_token\": \"...\"}",
+		AdditionalProperties: &jsonschema.Schema{Type: "string"},
+	}
+}
+
+// rolesSchema describes the user roles parameter
+func rolesSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "array",
+		Description: "Role names, e.g. Admin, Standard, Guest",
+		Items:       &jsonschema.Schema{Type: "string"},
+	}
+}
+
+func createUserTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "create_user",
+			Description: "Create a new user",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Create User",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"username", "password", "roles"},
+				Properties: map[string]*jsonschema.Schema{
+					"username": {
+						Type:        "string",
+						Description: "Login name",
+					},
+					"password": {
+						Type:        "string",
+						Description: "Initial password",
+					},
+					"nickname": {
+						Type:        "string",
+						Description: "Display name",
+					},
+					"phone": {
+						Type:        "string",
+						Description: "Phone number",
+					},
+					"email": {
+						Type:        "string",
+						Description: "Email address",
+					},
+					"roles":    rolesSchema(),
+					"contacts": contactsSchema(),
+				},
+			},
+			OutputSchema: toolset.OutputSchema[UserActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input CreateUserInput) (*mcp.CallToolResult, error) {
+			if strings.TrimSpace(input.Username) == "" {
+				return toolset.NewToolResultError("username is required"), nil
+			}
+			if input.Password == "" {
+				return toolset.NewToolResultError("password is required"), nil
+			}
+			if len(input.Roles) == 0 {
+				return toolset.NewToolResultError("roles is required"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			body := map[string]any{
+				"username": strings.TrimSpace(input.Username),
+				"password": input.Password,
+				"nickname": input.Nickname,
+				"phone":    input.Phone,
+				"email":    input.Email,
+				"roles":    input.Roles,
+				"contacts": input.Contacts,
+			}
+			if _, err := client.DoPost[any](c, ctx, "/api/n9e/users", body); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(UserActionResult{
+				Username: strings.TrimSpace(input.Username),
+				Message:  "User created successfully",
+			}), nil
+		}),
+	)
+}
+
+func updateUserTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "update_user",
+			Description: "Update a user's profile, contacts or roles. Omitted fields are left unchanged. Changing roles requires confirm=true. Nightingale has no disabled flag and roles cannot be empty, use disable_user to disable a user.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Update User",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "User ID",
+					},
+					"nickname": {
+						Type:        "string",
+						Description: "Display name",
+					},
+					"phone": {
+						Type:        "string",
+						Description: "Phone number",
+					},
+					"email": {
+						Type:        "string",
+						Description: "Email address",
+					},
+					"roles": func() *jsonschema.Schema {
+						s := rolesSchema()
+						s.Description = "New role names, replacing the current roles (requires confirm=true)"
+						return s
+					}(),
+					"contacts": func() *jsonschema.Schema {
+						s := contactsSchema()
+						s.Description = "Contacts to set, merged into the current contacts. An empty value removes the contact."
+						return s
+					}(),
+					"confirm": {
+						Type:        "boolean",
+						Description: "Must be true to change roles",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[UserActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UpdateUserInput) (*mcp.CallToolResult, error) {
+			if input.UserId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/user/%d/profile", input.UserId)
+			user, err := client.DoGet[types.User](c, ctx, path, nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			roles := user.Roles
+			if input.Roles != nil && !sameRoles(input.Roles, user.Roles) {
+				if !input.Confirm {
+					return toolset.NewToolResultError(fmt.Sprintf("changing roles of user %s from %v to %v requires confirm=true", user.Username, user.Roles, input.Roles)), nil
+				}
+				if len(input.Roles) == 0 {
+					return toolset.NewToolResultError("roles cannot be empty, Nightingale requires at least one role. Use disable_user to restrict a user to a read-only role"), nil
+				}
+				roles = input.Roles
+			}
+
+			body := map[string]any{
+				"nickname": user.Nickname,
+				"phone":    user.Phone,
+				"email":    user.Email,
+				"roles":    roles,
+				"contacts": mergeContacts(user.Contacts, input.Contacts),
+			}
+			if input.Nickname != nil {
+				body["nickname"] = *input.Nickname
+			}
+			if input.Phone != nil {
+				body["phone"] = *input.Phone
+			}
+			if input.Email != nil {
+				body["email"] = *input.Email
+			}
+
+			if _, err := client.DoPut[any](c, ctx, path, body); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(UserActionResult{
+				Id:      input.UserId,
+				Roles:   roles,
+				Message: "User updated successfully",
+			}), nil
+		}),
+	)
+}
+
+func disableUserTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "disable_user",
+			Description: "Disable a user by replacing all of their roles with a single restricted role (Guest by default). Nightingale has no disabled flag and requires every user to have a role, so this is the closest equivalent. The account, contacts and team memberships are kept, restore access with update_user. Requires confirm=true.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Disable User",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(true),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id", "confirm"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "User ID",
+					},
+					"role": {
+						Type:        "string",
+						Description: "Restricted role the user keeps (default Guest)",
+					},
+					"confirm": {
+						Type:        "boolean",
+						Description: "Must be true to revoke the user's roles",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[UserActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input DisableUserInput) (*mcp.CallToolResult, error) {
+			if input.UserId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+			role := strings.TrimSpace(input.Role)
+			if role == "" {
+				role = defaultDisabledUserRole
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/user/%d/profile", input.UserId)
+			user, err := client.DoGet[types.User](c, ctx, path, nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+			if sameRoles(user.Roles, []string{role}) {
+				return toolset.MarshalResult(UserActionResult{
+					Id:       input.UserId,
+					Username: user.Username,
+					Roles:    user.Roles,
+					Message:  fmt.Sprintf("User already only has the %s role", role),
+				}), nil
+			}
+			if !input.Confirm {
+				return toolset.NewToolResultError(fmt.Sprintf("disabling user %s replaces roles %v with %s and requires confirm=true", user.Username, user.Roles, role)), nil
+			}
+
+			body := map[string]any{
+				"nickname": user.Nickname,
+				"phone":    user.Phone,
+				"email":    user.Email,
+				"roles":    []string{role},
+				"contacts": user.Contacts,
+			}
+			if _, err := client.DoPut[any](c, ctx, path, body); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(UserActionResult{
+				Id:           input.UserId,
+				Username:     user.Username,
+				Roles:        []string{role},
+				RevokedRoles: user.Roles,
+				Message:      "User disabled successfully",
+			}), nil
+		}),
+	)
+}
+
+func createUserGroupTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "create_user_group",
+			Description: "Create a new user group (team)",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Create User Group",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"name"},
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "User group name",
+					},
+					"note": {
+						Type:        "string",
+						Description: "User group note",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input CreateUserGroupInput) (*mcp.CallToolResult, error) {
+			if strings.TrimSpace(input.Name) == "" {
+				return toolset.NewToolResultError("name is required"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			body := map[string]any{
+				"name": strings.TrimSpace(input.Name),
+				"note": input.Note,
+			}
+			result, err := client.DoPost[int64](c, ctx, "/api/n9e/user-groups", body)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      result,
+				Message: "User group created successfully",
+			}), nil
+		}),
+	)
+}
+
+func updateUserGroupTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "update_user_group",
+			Description: "Rename a user group (team) or change its note. Omitted fields are left unchanged.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Update User Group",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:     "object",
+				Required: []string{"id"},
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "User group ID",
+					},
+					"name": {
+						Type:        "string",
+						Description: "New user group name",
+					},
+					"note": {
+						Type:        "string",
+						Description: "New user group note",
+					},
+				},
+			},
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UpdateUserGroupInput) (*mcp.CallToolResult, error) {
+			if input.GroupId <= 0 {
+				return toolset.NewToolResultError("id is required and must be positive"), nil
+			}
+			if input.Name == nil && input.Note == nil {
+				return toolset.NewToolResultError("at least one of name and note is required"), nil
+			}
+
+			c := getClient(ctx)
+			if c == nil {
+				return toolset.NewToolResultError("failed to get n9e client from context"), nil
+			}
+
+			path := fmt.Sprintf("/api/n9e/user-group/%d", input.GroupId)
+			detail, err := client.DoGet[types.UserGroupDetail](c, ctx, path, nil)
+			if err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			body := map[string]any{
+				"name": detail.UserGroup.Name,
+				"note": detail.UserGroup.Note,
+			}
+			if input.Name != nil {
+				body["name"] = strings.TrimSpace(*input.Name)
+			}
+			if input.Note != nil {
+				body["note"] = *input.Note
+			}
+			if body["name"] == "" {
+				return toolset.NewToolResultError("name cannot be empty"), nil
+			}
+
+			if _, err := client.DoPut[any](c, ctx, path, body); err != nil {
+				return toolset.NewToolResultError(err.Error()), nil
+			}
+
+			return toolset.MarshalResult(types.ActionResult{
+				Id:      input.GroupId,
+				Message: "User group updated successfully",
+			}), nil
+		}),
+	)
+}
+
+// userGroupMembersSchema describes the add/remove user group members parameters
+func userGroupMembersSchema(idsDescription string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"id", "user_ids"},
+		Properties: map[string]*jsonschema.Schema{
+			"id": {
+				Type:        "integer",
+				Description: "User group ID",
+			},
+			"user_ids": {
+				Type:        "array",
+				Description: idsDescription,
+				Items:       &jsonschema.Schema{Type: "integer"},
+			},
+		},
+	}
+}
+
+func addUserGroupMembersTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "add_user_group_members",
+			Description: "Add users to a user group (team)",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Add User Group Members",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(false),
+				IdempotentHint:  true,
+			},
+			InputSchema:  userGroupMembersSchema("User IDs to add"),
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UserGroupMembersInput) (*mcp.CallToolResult, error) {
+			return runUserGroupMembersOp(ctx, getClient, input, "POST", "added")
+		}),
+	)
+}
+
+func removeUserGroupMembersTool(getClient client.GetClientFunc) toolset.ServerTool {
+	return toolset.NewServerTool(
+		mcp.Tool{
+			Name:        "remove_user_group_members",
+			Description: "Remove users from a user group (team)",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Remove User Group Members",
+				ReadOnlyHint:    false,
+				DestructiveHint: toolset.BoolPtr(true),
+				IdempotentHint:  true,
+			},
+			InputSchema:  userGroupMembersSchema("User IDs to remove"),
+			OutputSchema: toolset.OutputSchema[types.ActionResult](),
+		},
+		toolset.MakeToolHandler(func(ctx context.Context, req *mcp.CallToolRequest, input UserGroupMembersInput) (*mcp.CallToolResult, error) {
+			return runUserGroupMembersOp(ctx, getClient, input, "DELETE", "removed")
+		}),
+	)
+}
+
+// runUserGroupMembersOp adds (POST) or removes (DELETE) user group members
+func runUserGroupMembersOp(ctx context.Context, getClient client.GetClientFunc, input UserGroupMembersInput, method, done string) (*mcp.CallToolResult, error) {
+	if input.GroupId <= 0 {
+		return toolset.NewToolResultError("id is required and must be positive"), nil
+	}
+	if len(input.UserIds) == 0 {
+		return toolset.NewToolResultError("user_ids is required"), nil
+	}
+
+	c := getClient(ctx)
+	if c == nil {
+		return toolset.NewToolResultError("failed to get n9e client from context"), nil
+	}
+
+	path := fmt.Sprintf("/api/n9e/user-group/%d/members", input.GroupId)
+	body := map[string]any{"ids": input.UserIds}
+
+	var err error
+	if method == "DELETE" {
+		_, err = client.DoDelete[any](c, ctx, path, body)
+	} else {
+		_, err = client.DoPost[any](c, ctx, path, body)
+	}
+	if err != nil {
+		return toolset.NewToolResultError(err.Error()), nil
+	}
+
+	return toolset.MarshalResult(types.ActionResult{
+		Id:      input.GroupId,
+		Message: fmt.Sprintf("%d member(s) %s successfully", len(input.UserIds), done),
+	}), nil
+}
+
+// sameRoles checks whether two role lists contain the same roles, ignoring order
+func sameRoles(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// mergeContacts sets the given contacts on top of the current ones, an empty value removes the contact
+func mergeContacts(current map[string]any, updates map[string]string) map[string]any {
+	merged := make(map[string]any, len(current)+len(updates))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range updates {
+		if v == "" {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
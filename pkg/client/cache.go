@@ -0,0 +1,123 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultClientCacheTTL  = 30 * time.Minute
+	DefaultClientCacheSize = 1000
+)
+
+// cacheEntry is a cached client with its last access time
+type cacheEntry struct {
+	client   *Client
+	lastUsed time.Time
+}
+
+// ClientCache caches clients by token, all cached clients share one HTTP transport.
+// Entries idle longer than ttl are evicted, and the least recently used entry is
+// evicted when the cache is full.
+type ClientCache struct {
+	httpClient *http.Client
+	baseURL    *url.URL
+	userAgent  string
+	ttl        time.Duration
+	maxSize    int
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// NewClientCache creates a client cache for the given Nightingale base URL
+func NewClientCache(baseURL, userAgent string, ttl time.Duration, maxSize int) (*ClientCache, error) {
+	if baseURL == "" {
+		baseURL = "http://localhost:17000"
+	}
+
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultClientCacheTTL
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultClientCacheSize
+	}
+
+	return &ClientCache{
+		httpClient: newHTTPClient(),
+		baseURL:    parsedURL,
+		userAgent:  userAgent,
+		ttl:        ttl,
+		maxSize:    maxSize,
+		entries:    make(map[string]*cacheEntry),
+	}, nil
+}
+
+// Get returns the cached client for token, creating it if needed
+func (cc *ClientCache) Get(token string) (*Client, error) {
+	if token == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	now := time.Now()
+	if e, ok := cc.entries[token]; ok && now.Sub(e.lastUsed) < cc.ttl {
+		e.lastUsed = now
+		return e.client, nil
+	}
+
+	cc.evictLocked(now)
+
+	c := &Client{
+		httpClient: cc.httpClient,
+		baseURL:    cc.baseURL,
+		token:      token,
+		userAgent:  cc.userAgent,
+	}
+	cc.entries[token] = &cacheEntry{client: c, lastUsed: now}
+
+	return c, nil
+}
+
+// Evict removes the cached client for token
+func (cc *ClientCache) Evict(token string) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	delete(cc.entries, token)
+}
+
+// Len returns the number of cached clients
+func (cc *ClientCache) Len() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return len(cc.entries)
+}
+
+// evictLocked removes expired entries, and the least recently used entry if still full
+func (cc *ClientCache) evictLocked(now time.Time) {
+	var oldestToken string
+	var oldest time.Time
+	for token, e := range cc.entries {
+		if now.Sub(e.lastUsed) >= cc.ttl {
+			delete(cc.entries, token)
+			continue
+		}
+		if oldestToken == "" || e.lastUsed.Before(oldest) {
+			oldestToken, oldest = token, e.lastUsed
+		}
+	}
+
+	if len(cc.entries) >= cc.maxSize && oldestToken != "" {
+		delete(cc.entries, oldestToken)
+	}
+}
@@ -3,6 +3,8 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -48,20 +50,31 @@ func NewClient(token, baseURL, userAgent string) (*Client, error) {
 	}
 
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: DefaultTimeout,
-			Transport: &http.Transport{
-				MaxIdleConns:        100,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-			},
-		},
-		baseURL:   parsedURL,
-		token:     token,
-		userAgent: userAgent,
+		httpClient: newHTTPClient(),
+		baseURL:    parsedURL,
+		token:      token,
+		userAgent:  userAgent,
 	}, nil
 }
 
+// newHTTPClient creates the underlying HTTP client with connection pooling
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: DefaultTimeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// Identity returns a key identifying the instance and token the client acts for, without exposing the token
+func (c *Client) Identity() string {
+	sum := sha256.Sum256([]byte(c.token))
+	return c.baseURL.String() + "#" + hex.EncodeToString(sum[:])
+}
+
 // SetUserAgent sets the User-Agent
 func (c *Client) SetUserAgent(userAgent string) {
 	c.userAgent = userAgent
@@ -162,7 +175,7 @@ func (c *Client) makeRequest(ctx context.Context, method, path string, params ur
 			return nil, resp.StatusCode, requestID, lastErr
 
 		case resp.StatusCode >= 400: // 4xx client errors are not retryable
-			return nil, resp.StatusCode, requestID, fmt.Errorf("client error: %d %s", resp.StatusCode, string(bodyBytes))
+			return nil, resp.StatusCode, requestID, &StatusError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
 
 		default:
 			return nil, resp.StatusCode, requestID, fmt.Errorf("unexpected status: %d", resp.StatusCode)
@@ -237,6 +250,38 @@ func DoGet[T any](c *Client, ctx context.Context, path string, params url.Values
 	return resp.Dat, nil
 }
 
+// DoGetRaw executes GET request for APIs not using the Nightingale response format,
+// such as the datasource proxy, and unmarshals the response body as is
+func DoGetRaw[T any](c *Client, ctx context.Context, path string, params url.Values) (T, error) {
+	return doRaw[T](c, ctx, "GET", path, params, nil)
+}
+
+// DoPostRaw executes POST request for APIs not using the Nightingale response format
+func DoPostRaw[T any](c *Client, ctx context.Context, path string, body any) (T, error) {
+	return doRaw[T](c, ctx, "POST", path, nil, body)
+}
+
+// doRaw executes request and unmarshals the response body as is
+func doRaw[T any](c *Client, ctx context.Context, method, path string, params url.Values, body any) (T, error) {
+	var zero T
+
+	bodyBytes, _, _, err := c.makeRequest(ctx, method, path, params, body)
+	if err != nil {
+		return zero, err
+	}
+
+	var resp T
+	if err := json.Unmarshal(bodyBytes, &resp); err != nil {
+		preview := string(bodyBytes)
+		if len(preview) > 200 {
+			preview = preview[:200] + "..."
+		}
+		return zero, fmt.Errorf("failed to unmarshal response: %w, response preview: %s", err, preview)
+	}
+
+	return resp, nil
+}
+
 // DoPost executes POST request
 func DoPost[T any](c *Client, ctx context.Context, path string, body any) (T, error) {
 	var zero T
@@ -8,9 +8,25 @@ import (
 type contextKey string
 
 const (
-	clientContextKey contextKey = "n9e_client"
+	clientContextKey        contextKey = "n9e_client"
+	instanceContextKey      contextKey = "n9e_instance"
+	sessionTokensContextKey contextKey = "n9e_session_tokens"
 )
 
+// SessionTokens represents the N9E user tokens brought by a session
+type SessionTokens struct {
+	Default     string            // Token for any instance without its own
+	PerInstance map[string]string // Tokens by instance name
+}
+
+// For returns the session token for the named instance
+func (t SessionTokens) For(instance string) string {
+	if token := t.PerInstance[instance]; token != "" {
+		return token
+	}
+	return t.Default
+}
+
 // GetClientFunc is the function type for getting Client from context
 type GetClientFunc func(ctx context.Context) *Client
 
@@ -41,3 +57,25 @@ func MustClientFromContext(ctx context.Context) *Client {
 func DefaultGetClient(ctx context.Context) *Client {
 	return ClientFromContext(ctx)
 }
+
+// ContextWithInstance injects the target instance name into context
+func ContextWithInstance(ctx context.Context, instance string) context.Context {
+	return context.WithValue(ctx, instanceContextKey, instance)
+}
+
+// InstanceFromContext gets the target instance name from context (empty for default)
+func InstanceFromContext(ctx context.Context) string {
+	instance, _ := ctx.Value(instanceContextKey).(string)
+	return instance
+}
+
+// ContextWithSessionTokens injects session tokens into context
+func ContextWithSessionTokens(ctx context.Context, tokens SessionTokens) context.Context {
+	return context.WithValue(ctx, sessionTokensContextKey, tokens)
+}
+
+// SessionTokensFromContext gets session tokens from context
+func SessionTokensFromContext(ctx context.Context) SessionTokens {
+	tokens, _ := ctx.Value(sessionTokensContextKey).(SessionTokens)
+	return tokens
+}
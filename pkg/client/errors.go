@@ -44,6 +44,28 @@ func (e *APIError) Error() string {
 	return sb.String()
 }
 
+// StatusError represents a non-retryable 4xx HTTP response
+type StatusError struct {
+	StatusCode int    // HTTP status code
+	Body       string // Response body
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("client error: %d %s", e.StatusCode, e.Body)
+}
+
+// HTTPStatus returns the HTTP status code carried by a StatusError or APIError, or 0
+func HTTPStatus(err error) int {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	if apiErr := GetAPIError(err); apiErr != nil {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
 // IsAPIError checks if the error is an API business error
 func IsAPIError(err error) bool {
 	var apiErr *APIError
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// DefaultInstanceName is the instance name used when only --base-url/--token are configured
+const DefaultInstanceName = "default"
+
+// InstanceConfig represents a named Nightingale instance (cluster)
+type InstanceConfig struct {
+	BaseURL string `json:"base_url" mapstructure:"base_url"`
+	Token   string `json:"token" mapstructure:"token"`
+}
+
+// instance is a configured Nightingale instance and its per-token client cache
+type instance struct {
+	baseURL string
+	token   string
+	clients *ClientCache
+}
+
+// Registry resolves clients for multiple named Nightingale instances
+type Registry struct {
+	instances   map[string]*instance
+	defaultName string
+}
+
+// NewRegistry creates a registry of Nightingale instances.
+// defaultName may be empty if there is exactly one instance.
+func NewRegistry(instances map[string]InstanceConfig, defaultName, userAgent string) (*Registry, error) {
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("at least one instance is required")
+	}
+
+	r := &Registry{
+		instances:   make(map[string]*instance, len(instances)),
+		defaultName: defaultName,
+	}
+	for name, cfg := range instances {
+		if name == "" {
+			return nil, fmt.Errorf("instance name is required")
+		}
+		clients, err := NewClientCache(cfg.BaseURL, userAgent, DefaultClientCacheTTL, DefaultClientCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("instance %s: %w", name, err)
+		}
+		r.instances[name] = &instance{
+			baseURL: clients.baseURL.String(),
+			token:   cfg.Token,
+			clients: clients,
+		}
+	}
+
+	if r.defaultName == "" {
+		if len(instances) > 1 {
+			return nil, fmt.Errorf("default instance is required when multiple instances are configured")
+		}
+		for name := range instances {
+			r.defaultName = name
+		}
+	}
+	if _, ok := r.instances[r.defaultName]; !ok {
+		return nil, fmt.Errorf("unknown default instance: %s", r.defaultName)
+	}
+
+	return r, nil
+}
+
+// Names returns all instance names in sorted order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.instances))
+	for name := range r.instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultName returns the default instance name
+func (r *Registry) DefaultName() string {
+	return r.defaultName
+}
+
+// Has checks if the instance exists
+func (r *Registry) Has(name string) bool {
+	_, ok := r.instances[name]
+	return ok
+}
+
+// BaseURL returns the base URL of the instance
+func (r *Registry) BaseURL(name string) string {
+	if inst, ok := r.instances[name]; ok {
+		return inst.baseURL
+	}
+	return ""
+}
+
+// Client returns the client of the named instance (empty for default).
+// sessionToken takes precedence over the instance's configured token.
+func (r *Registry) Client(name, sessionToken string) (*Client, error) {
+	if name == "" {
+		name = r.defaultName
+	}
+
+	inst, ok := r.instances[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown instance: %s, available instances: %v", name, r.Names())
+	}
+
+	token := sessionToken
+	if token == "" {
+		token = inst.token
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no token for instance %s", name)
+	}
+
+	return inst.clients.Get(token)
+}
+
+// GetClient is a GetClientFunc resolving the client by the instance and session tokens in context.
+// A client injected by ContextWithClient takes precedence.
+func (r *Registry) GetClient(ctx context.Context) *Client {
+	if c := ClientFromContext(ctx); c != nil {
+		return c
+	}
+
+	name := InstanceFromContext(ctx)
+	if name == "" {
+		name = r.defaultName
+	}
+
+	c, err := r.Client(name, SessionTokensFromContext(ctx).For(name))
+	if err != nil {
+		return nil
+	}
+	return c
+}
@@ -2,10 +2,36 @@ package toolset
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
 
+	"github.com/n9e/n9e-mcp-server/pkg/types"
+
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// resultProperty is the property wrapping tool results that are not JSON objects,
+// as MCP structured content must be an object
+const resultProperty = "result"
+
+// outputTypeSchemas holds the schemas of types that cannot be inferred.
+// Business groups and user groups reference each other, so the business groups
+// of the member teams of a business group are untyped objects.
+var outputTypeSchemas = func() map[reflect.Type]*jsonschema.Schema {
+	member, err := jsonschema.For[types.UserGroupWithPermFlag](&jsonschema.ForOptions{
+		TypeSchemas: map[reflect.Type]*jsonschema.Schema{
+			reflect.TypeFor[types.BusiGroup](): {Type: "object"},
+		},
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to generate schema for user group members: %v", err))
+	}
+	return map[reflect.Type]*jsonschema.Schema{
+		reflect.TypeFor[types.UserGroupWithPermFlag](): member,
+	}
+}()
+
 // BoolPtr returns a pointer to bool
 func BoolPtr(b bool) *bool {
 	return &b
@@ -24,13 +50,85 @@ func StringPtr(s string) *string {
 	return &s
 }
 
-// MarshalResult serializes result to JSON and returns MCP tool result
+// MarshalResult returns v as the structured content of the MCP tool result, with compact JSON as its text rendering.
+// The text mirrors the structured content in full, as MCP recommends, since clients without structured output support only read the text.
+// Results that are not JSON objects are wrapped in a "result" property, matching OutputSchema.
 func MarshalResult(v any) *mcp.CallToolResult {
-	data, err := json.MarshalIndent(v, "", "  ")
+	data, err := json.Marshal(v)
 	if err != nil {
 		return NewToolResultError("failed to marshal result: " + err.Error())
 	}
-	return NewToolResultText(string(data))
+	structured := json.RawMessage(data)
+	if !isObjectType(reflect.TypeOf(v)) {
+		structured = json.RawMessage(`{"` + resultProperty + `":` + string(data) + `}`)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: string(data)},
+		},
+		StructuredContent: structured,
+	}
+}
+
+// OutputSchema generates the output schema of tool results of type T, as returned by MarshalResult
+func OutputSchema[T any]() *jsonschema.Schema {
+	schema, err := jsonschema.For[T](&jsonschema.ForOptions{TypeSchemas: outputTypeSchemas})
+	if err != nil {
+		panic(fmt.Sprintf("failed to generate output schema for %v: %v", reflect.TypeFor[T](), err))
+	}
+	nullableMaps(schema)
+	if isObjectType(reflect.TypeFor[T]()) {
+		// Pointers and maps are nullable, results never are
+		schema.Type = "object"
+		schema.Types = nil
+		return schema
+	}
+	return &jsonschema.Schema{
+		Type:       "object",
+		Required:   []string{resultProperty},
+		Properties: map[string]*jsonschema.Schema{resultProperty: schema},
+	}
+}
+
+// nullableMaps allows null for the schemas of maps, as nil maps are serialized as null.
+// Maps are the object schemas without properties whose additional properties are allowed.
+func nullableMaps(s *jsonschema.Schema) {
+	if s == nil {
+		return
+	}
+	if s.Type == "object" && s.Properties == nil && s.AdditionalProperties != nil && s.AdditionalProperties.Not == nil {
+		s.Types = []string{"null", "object"}
+		s.Type = ""
+	}
+	for _, p := range s.Properties {
+		nullableMaps(p)
+	}
+	for _, item := range s.PrefixItems {
+		nullableMaps(item)
+	}
+	nullableMaps(s.Items)
+	nullableMaps(s.AdditionalProperties)
+}
+
+// isObjectType reports whether values of type t are serialized as JSON objects
+func isObjectType(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t != nil && (t.Kind() == reflect.Struct || t.Kind() == reflect.Map)
+}
+
+// MarshalResource serializes v to JSON and returns it as the contents of the resource uri
+func MarshalResource(uri string, v any) (*mcp.ReadResourceResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: uri, MIMEType: "application/json", Text: string(data)},
+		},
+	}, nil
 }
 
 // NewToolResultText creates a text result
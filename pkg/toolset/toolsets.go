@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // DefaultToolsets is the default enabled toolsets
-var DefaultToolsets = []string{"alerts", "targets", "datasource", "mutes", "busi_groups", "notify_rules", "alert_subscribes", "event_pipelines", "users"}
+var DefaultToolsets = []string{"alerts", "targets", "datasource", "mutes", "busi_groups", "notify_rules", "notify_channels", "alert_subscribes", "event_pipelines", "users"}
 
 // ServerTool wraps MCP tool and its handler function
 type ServerTool struct {
@@ -26,21 +27,53 @@ func NewServerTool(tool mcp.Tool, handler mcp.ToolHandler) ServerTool {
 	}
 }
 
+// ServerResourceTemplate wraps MCP resource template and its handler function
+type ServerResourceTemplate struct {
+	Template mcp.ResourceTemplate
+	Handler  mcp.ResourceHandler
+}
+
+// NewServerResourceTemplate creates a ServerResourceTemplate
+func NewServerResourceTemplate(template mcp.ResourceTemplate, handler mcp.ResourceHandler) ServerResourceTemplate {
+	return ServerResourceTemplate{
+		Template: template,
+		Handler:  handler,
+	}
+}
+
+// ServerPrompt wraps MCP prompt and its handler function
+type ServerPrompt struct {
+	Prompt  mcp.Prompt
+	Handler mcp.PromptHandler
+}
+
+// NewServerPrompt creates a ServerPrompt
+func NewServerPrompt(prompt mcp.Prompt, handler mcp.PromptHandler) ServerPrompt {
+	return ServerPrompt{
+		Prompt:  prompt,
+		Handler: handler,
+	}
+}
+
 // Toolset represents a toolset
 type Toolset struct {
-	Name        string
-	Description string
-	ReadTools   []ServerTool
-	WriteTools  []ServerTool
+	Name              string
+	Description       string
+	ReadTools         []ServerTool
+	WriteTools        []ServerTool
+	ResourceTemplates []ServerResourceTemplate
+	Prompts           []ServerPrompt
 }
 
 // NewToolset creates a toolset
 func NewToolset(name, description string) *Toolset {
 	return &Toolset{
-		Name:        name,
-		Description: description,
-		ReadTools:   make([]ServerTool, 0),
-		WriteTools:  make([]ServerTool, 0),
+		Name:              name,
+		Description:       description,
+		ReadTools:         make([]ServerTool, 0),
+		WriteTools:        make([]ServerTool, 0),
+		ResourceTemplates: make([]ServerResourceTemplate, 0),
+		Prompts:           make([]ServerPrompt, 0),
 	}
 }
 
@@ -56,11 +89,27 @@ func (t *Toolset) AddWriteTools(tools ...ServerTool) *Toolset {
 	return t
 }
 
+// AddResourceTemplates adds resource templates, they are read-only and available in read-only mode
+func (t *Toolset) AddResourceTemplates(templates ...ServerResourceTemplate) *Toolset {
+	t.ResourceTemplates = append(t.ResourceTemplates, templates...)
+	return t
+}
+
+// AddPrompts adds prompts, they only read data and are available in read-only mode
+func (t *Toolset) AddPrompts(prompts ...ServerPrompt) *Toolset {
+	t.Prompts = append(t.Prompts, prompts...)
+	return t
+}
+
+// InstanceParam is the optional tool argument selecting the target Nightingale instance
+const InstanceParam = "instance"
+
 // ToolsetGroup represents a toolset group
 type ToolsetGroup struct {
-	toolsets map[string]*Toolset
-	enabled  map[string]bool
-	readOnly bool
+	toolsets  map[string]*Toolset
+	enabled   map[string]bool
+	readOnly  bool
+	instances []string
 }
 
 // NewToolsetGroup creates a toolset group
@@ -104,7 +153,23 @@ func (g *ToolsetGroup) EnableToolsets(names []string) error {
 	return nil
 }
 
-// RegisterAll registers all enabled tools to MCP Server
+// SetInstances sets the available Nightingale instance names.
+// When more than one instance is available, every tool gets an optional instance argument.
+func (g *ToolsetGroup) SetInstances(names []string) {
+	g.instances = names
+}
+
+// IsEnabled reports whether the named toolset is enabled
+func (g *ToolsetGroup) IsEnabled(name string) bool {
+	return g.enabled[name]
+}
+
+// IsReadOnly reports whether write tools are left out
+func (g *ToolsetGroup) IsReadOnly() bool {
+	return g.readOnly
+}
+
+// RegisterAll registers all enabled tools, resource templates and prompts to MCP Server
 func (g *ToolsetGroup) RegisterAll(s *mcp.Server) {
 	for name, toolset := range g.toolsets {
 		if !g.enabled[name] {
@@ -113,18 +178,59 @@ func (g *ToolsetGroup) RegisterAll(s *mcp.Server) {
 
 		// Register read-only tools
 		for _, st := range toolset.ReadTools {
-			tool := st.Tool
+			tool := g.withInstanceParam(st.Tool)
 			s.AddTool(&tool, st.Handler)
 		}
 
 		// If not read-only mode, register write tools
 		if !g.readOnly {
 			for _, st := range toolset.WriteTools {
-				tool := st.Tool
+				tool := g.withInstanceParam(st.Tool)
 				s.AddTool(&tool, st.Handler)
 			}
 		}
+
+		// Register resource templates, served from the default instance
+		for _, rt := range toolset.ResourceTemplates {
+			template := rt.Template
+			s.AddResourceTemplate(&template, rt.Handler)
+		}
+
+		// Register prompts, served from the default instance
+		for _, sp := range toolset.Prompts {
+			prompt := sp.Prompt
+			s.AddPrompt(&prompt, sp.Handler)
+		}
+	}
+}
+
+// withInstanceParam adds the optional instance argument to the tool input schema
+func (g *ToolsetGroup) withInstanceParam(tool mcp.Tool) mcp.Tool {
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	if len(g.instances) <= 1 || !ok || schema == nil {
+		return tool
+	}
+	if _, exists := schema.Properties[InstanceParam]; exists {
+		return tool
+	}
+
+	enum := make([]any, 0, len(g.instances))
+	for _, name := range g.instances {
+		enum = append(enum, name)
+	}
+
+	s := *schema
+	s.Properties = make(map[string]*jsonschema.Schema, len(schema.Properties)+1)
+	for k, v := range schema.Properties {
+		s.Properties[k] = v
+	}
+	s.Properties[InstanceParam] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Target Nightingale instance (default instance if omitted)",
+		Enum:        enum,
 	}
+	tool.InputSchema = &s
+	return tool
 }
 
 // GetAvailableToolsets gets all available toolset names
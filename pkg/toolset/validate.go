@@ -2,6 +2,7 @@ package toolset
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -12,8 +13,34 @@ var (
 	ValidCates      = map[string]bool{"prometheus": true, "host": true, "elasticsearch": true, "loki": true, "$all": true}
 	ValidRuleProds  = map[string]bool{"host": true, "metric": true, "loki": true, "anomaly": true}
 	ValidRecovered  = map[int]bool{-1: true, 0: true, 1: true}
+
+	// ValidRuleCates are the datasource categories of alert rules and subscriptions, which have no "$all"
+	ValidRuleCates = map[string]bool{
+		"prometheus": true, "host": true, "elasticsearch": true, "opensearch": true, "loki": true,
+		"tdengine": true, "ck": true, "mysql": true, "pgsql": true, "doris": true, "victorialogs": true,
+	}
 )
 
+// enumKeys returns the values of a string enumeration sorted
+func enumKeys(valid map[string]bool) []string {
+	keys := make([]string, 0, len(valid))
+	for k := range valid {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EnumValues returns the values of a string enumeration sorted, for use as a JSON schema enum
+func EnumValues(valid map[string]bool) []any {
+	keys := enumKeys(valid)
+	values := make([]any, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, k)
+	}
+	return values
+}
+
 // ValidateTimeRange validates time range
 func ValidateTimeRange(hours int64, stime, etime int64) error {
 	// hours and stime/etime are mutually exclusive
@@ -65,6 +92,17 @@ func ValidateCate(cate string) error {
 	return nil
 }
 
+// ValidateRuleCate validates the cate of an alert rule or subscription
+func ValidateRuleCate(cate string) error {
+	if cate == "" {
+		return nil
+	}
+	if !ValidRuleCates[cate] {
+		return fmt.Errorf("invalid cate: %s, valid values: %s", cate, strings.Join(enumKeys(ValidRuleCates), ", "))
+	}
+	return nil
+}
+
 // ValidateRuleProds validates rule_prods enum (comma-separated multiple values)
 func ValidateRuleProds(ruleProds string) error {
 	if ruleProds == "" {
@@ -1,5 +1,7 @@
 package types
 
+import "encoding/json"
+
 // N9eResponse represents Nightingale unified response format
 type N9eResponse[T any] struct {
 	Dat T      `json:"dat"`
@@ -18,6 +20,14 @@ type IdName struct {
 	Name string `json:"name"`
 }
 
+// ActionResult represents the result of a write operation
+type ActionResult struct {
+	Id      int64   `json:"id,omitempty"`
+	Ids     []int64 `json:"ids,omitempty"`
+	Name    string  `json:"name,omitempty"`
+	Message string  `json:"message"`
+}
+
 // AlertCurEvent represents active alert event
 type AlertCurEvent struct {
 	// Basic identification
@@ -158,14 +168,48 @@ type PipelineConfig struct {
 
 // NotifyConfig represents notification configuration
 type NotifyConfig struct {
-	ChannelID  int64          `json:"channel_id"`
-	TemplateID int64          `json:"template_id"`
-	Params     map[string]any `json:"params"`
-	Type       string         `json:"type"`
-	Severities []int          `json:"severities"`
-	TimeRanges []TimeRange    `json:"time_ranges"`
-	LabelKeys  []TagFilter    `json:"label_keys"`
-	Attributes []TagFilter    `json:"attributes"`
+	ChannelID    int64          `json:"channel_id"`
+	ChannelName  string         `json:"channel_name,omitempty"`
+	TemplateID   int64          `json:"template_id"`
+	TemplateName string         `json:"template_name,omitempty"`
+	Params       map[string]any `json:"params"`
+	Type         string         `json:"type"`
+	Severities   []int          `json:"severities"`
+	TimeRanges   []TimeRange    `json:"time_ranges"`
+	LabelKeys    []TagFilter    `json:"label_keys"`
+	Attributes   []TagFilter    `json:"attributes"`
+}
+
+// NotifyChannel represents notification channel configuration (request_config is omitted as it may hold credentials)
+type NotifyChannel struct {
+	Id          int64  `json:"id"`
+	Name        string `json:"name"`
+	Ident       string `json:"ident"`
+	Description string `json:"description"`
+	Enable      bool   `json:"enable"`
+	ParamConfig any    `json:"param_config,omitempty"`
+	RequestType string `json:"request_type"`
+	Weight      int    `json:"weight"`
+	CreateAt    int64  `json:"create_at"`
+	CreateBy    string `json:"create_by"`
+	UpdateAt    int64  `json:"update_at"`
+	UpdateBy    string `json:"update_by"`
+}
+
+// MessageTemplate represents notification message template
+type MessageTemplate struct {
+	Id                 int64             `json:"id"`
+	Name               string            `json:"name"`
+	Ident              string            `json:"ident"`
+	Content            map[string]string `json:"content"`
+	UserGroupIds       []int64           `json:"user_group_ids"`
+	NotifyChannelIdent string            `json:"notify_channel_ident"`
+	Private            int               `json:"private"`
+	Weight             int               `json:"weight"`
+	CreateAt           int64             `json:"create_at"`
+	CreateBy           string            `json:"create_by"`
+	UpdateAt           int64             `json:"update_at"`
+	UpdateBy           string            `json:"update_by"`
 }
 
 // TimeRange represents time range
@@ -315,14 +359,28 @@ type AlertMute struct {
 
 // BusiGroup represents business group
 type BusiGroup struct {
-	Id          int64  `json:"id"`
-	Name        string `json:"name"`
-	LabelEnable int    `json:"label_enable"`
-	LabelValue  string `json:"label_value"`
-	CreateAt    int64  `json:"create_at"`
-	CreateBy    string `json:"create_by"`
-	UpdateAt    int64  `json:"update_at"`
-	UpdateBy    string `json:"update_by"`
+	Id          int64                   `json:"id"`
+	Name        string                  `json:"name"`
+	LabelEnable int                     `json:"label_enable"`
+	LabelValue  string                  `json:"label_value"`
+	CreateAt    int64                   `json:"create_at"`
+	CreateBy    string                  `json:"create_by"`
+	UpdateAt    int64                   `json:"update_at"`
+	UpdateBy    string                  `json:"update_by"`
+	UserGroups  []UserGroupWithPermFlag `json:"user_groups,omitempty"`
+}
+
+// BusiGroupMember represents a user group's permission on a business group
+type BusiGroupMember struct {
+	BusiGroupId int64  `json:"busi_group_id"`
+	UserGroupId int64  `json:"user_group_id"`
+	PermFlag    string `json:"perm_flag"`
+}
+
+// UserGroupWithPermFlag represents a member user group of a business group
+type UserGroupWithPermFlag struct {
+	UserGroup *UserGroup `json:"user_group"`
+	PermFlag  string     `json:"perm_flag"`
 }
 
 // EventPipeline represents event processing pipeline
@@ -391,6 +449,20 @@ type EventPipelineExecution struct {
 	InputsSnapshot string `json:"inputs_snapshot,omitempty"`
 }
 
+// NodeResult represents execution result of a single pipeline node or processor
+type NodeResult struct {
+	NodeId     string `json:"node_id"`
+	NodeName   string `json:"node_name,omitempty"`
+	NodeType   string `json:"node_type,omitempty"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	StartedAt  int64  `json:"started_at,omitempty"`
+	FinishedAt int64  `json:"finished_at,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Output     any    `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
 // User represents user
 type User struct {
 	Id             int64          `json:"id"`
@@ -436,3 +508,59 @@ type PeriodicMute struct {
 	EnableEtime      string `json:"enable_etime"`
 	EnableDaysOfWeek string `json:"enable_days_of_week"`
 }
+
+// PromResponse represents Prometheus HTTP API response format (returned by datasource proxy)
+type PromResponse[T any] struct {
+	Status    string   `json:"status"`
+	Data      T        `json:"data"`
+	ErrorType string   `json:"errorType,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// PromQueryData represents Prometheus query result data
+type PromQueryData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// PromSample represents a series of Prometheus instant vector result
+type PromSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]any            `json:"value"`
+}
+
+// PromSeries represents a series of Prometheus range vector (matrix) result
+type PromSeries struct {
+	Metric map[string]string `json:"metric"`
+	Values [][2]any          `json:"values"`
+}
+
+// LokiQueryData represents Loki query result data
+type LokiQueryData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// LokiStream represents a Loki log stream with [nanosecond timestamp, line] entries
+type LokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// ESSearchResponse represents Elasticsearch search response
+type ESSearchResponse struct {
+	TimedOut bool `json:"timed_out"`
+	Hits     struct {
+		Total any     `json:"total"`
+		Hits  []ESHit `json:"hits"`
+	} `json:"hits"`
+	Error any `json:"error,omitempty"`
+}
+
+// ESHit represents an Elasticsearch search hit
+type ESHit struct {
+	Index  string         `json:"_index"`
+	Id     string         `json:"_id"`
+	Source map[string]any `json:"_source"`
+}